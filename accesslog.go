@@ -0,0 +1,96 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	kHTTPIP        = "ip"
+	kHTTPUserAgent = "userAgent"
+	kHTTPRequestID = "requestId"
+)
+
+// AccessLogType is the "type" of records written by AccessLog.
+const AccessLogType = "access.log"
+
+// AccessLog is a record describing a completed HTTP request, with a fixed schema so access logs are
+// uniform across functions.
+//
+// The fields are written in an "http" group, and the record uses the "access.log" type. Responses
+// with a 5xx status are logged at ERROR, 4xx at WARN, and everything else at INFO.
+type AccessLog struct {
+	Method    string
+	Path      string
+	Status    int
+	Bytes     int64
+	Latency   time.Duration
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// NewAccessLog creates an AccessLog from a request, filling in the method, path, client IP, user
+// agent, and request ID. The client IP is taken from the first X-Forwarded-For address when present.
+// The request ID is the Lambda request ID when available, or the X-Request-Id header otherwise.
+func NewAccessLog(r *http.Request) AccessLog {
+	a := AccessLog{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		UserAgent: r.UserAgent(),
+		RequestID: requestIDFromContext(r.Context()),
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		a.IP, _, _ = strings.Cut(forwarded, ",")
+		a.IP = strings.TrimSpace(a.IP)
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		a.IP = host
+	} else {
+		a.IP = r.RemoteAddr
+	}
+
+	if a.RequestID == "" {
+		a.RequestID = r.Header.Get("X-Request-Id")
+	}
+
+	return a
+}
+
+// Level returns the level the access log is written at, based on the response status.
+func (a AccessLog) Level() slog.Level {
+	switch {
+	case a.Status >= 500:
+		return slog.LevelError
+	case a.Status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Attrs returns the attributes of the access log.
+func (a AccessLog) Attrs() []slog.Attr {
+	return []slog.Attr{
+		Type(AccessLogType),
+		slog.Group(kHTTPGroup,
+			slog.String(kHTTPMethod, a.Method),
+			slog.String(kHTTPPath, a.Path),
+			slog.Int(kHTTPStatus, a.Status),
+			slog.Int64(kHTTPBytes, a.Bytes),
+			slog.Duration(kHTTPLatency, a.Latency),
+			slog.String(kHTTPIP, a.IP),
+			slog.String(kHTTPUserAgent, a.UserAgent),
+			slog.String(kHTTPRequestID, a.RequestID),
+		),
+	}
+}
+
+// Log writes the access log to logger.
+func (a AccessLog) Log(ctx context.Context, logger *slog.Logger) {
+	logger.LogAttrs(ctx, a.Level(), "request completed", a.Attrs()...)
+}
@@ -0,0 +1,61 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAccessLog(t *testing.T) {
+	t.Run("from the remote address", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/path?query=1", nil)
+		request.RemoteAddr = "192.0.2.1:1234"
+		request.Header.Set("User-Agent", "test-agent")
+		request.Header.Set("X-Request-Id", "req-1")
+
+		access := sloglambda.NewAccessLog(request)
+
+		assert.Equal(t, sloglambda.AccessLog{
+			Method:    http.MethodGet,
+			Path:      "/path",
+			IP:        "192.0.2.1",
+			UserAgent: "test-agent",
+			RequestID: "req-1",
+		}, access)
+	})
+
+	t.Run("from a forwarded address", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Forwarded-For", "198.51.100.7, 10.0.0.1")
+
+		assert.Equal(t, "198.51.100.7", sloglambda.NewAccessLog(request).IP)
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	cases := map[int]string{
+		200: `"level":"INFO"`,
+		404: `"level":"WARN"`,
+		503: `"level":"ERROR"`,
+	}
+
+	for status, level := range cases {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+			sloglambda.AccessLog{Method: "GET", Path: "/", Status: status, Latency: time.Second}.Log(context.Background(), logger)
+
+			assert.Contains(t, buffer.String(), level)
+			assert.Contains(t, buffer.String(), `"type":"access.log"`)
+			assert.Contains(t, buffer.String(), `"latency":"1s"`)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package sloglambda
+
+import "log/slog"
+
+type alarmMarker struct {
+	level slog.Level
+	token string
+}
+
+// WithAlarmMarker configures the Handler to prefix the message of records at or above level with
+// token, for example "[ALARM]", separated by a space.
+//
+// The fixed token lets simple CloudWatch metric filters count the records alarms should fire on,
+// such as the filter pattern "[ALARM]" for text logs or { $.msg = "[ALARM]*" } for JSON logs,
+// without depending on the record's level names or structure.
+func WithAlarmMarker(level slog.Level, token string) Option {
+	return func(h *Handler) {
+		if token == "" {
+			h.invalidOption("WithAlarmMarker: empty token")
+			return
+		}
+		h.alarm = &alarmMarker{level: level, token: token}
+	}
+}
+
+// message returns the message written for the record.
+func (h *Handler) message(record slog.Record) string {
+	if h.alarm != nil && record.Level >= h.alarm.level {
+		return h.alarm.token + " " + record.Message
+	}
+	return record.Message
+}
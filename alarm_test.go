@@ -0,0 +1,25 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAlarmMarker(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithAlarmMarker(slog.LevelError, "[ALARM]")))
+
+	logger.Warn("retrying")
+	logger.Error("payment failed")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"retrying"`)
+	assert.Contains(t, lines[1], `"msg":"[ALARM] payment failed"`)
+}
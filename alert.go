@@ -0,0 +1,138 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// DefaultAlertTimeout is the default time an AlertHandler waits for a record to be published.
+const DefaultAlertTimeout = 2 * time.Second
+
+// Publisher publishes an encoded record to an alerting channel, such as an SNS topic or SQS queue.
+//
+// Implementations typically wrap an AWS SDK client, for example calling sns.Client.Publish with
+// the record as the message.
+type Publisher interface {
+	Publish(ctx context.Context, message []byte) error
+}
+
+// PublisherFunc adapts a function to a Publisher.
+type PublisherFunc func(ctx context.Context, message []byte) error
+
+// Publish implements Publisher.
+func (f PublisherFunc) Publish(ctx context.Context, message []byte) error {
+	return f(ctx, message)
+}
+
+// ErrAlertTimeout is reported when publishing a record takes longer than the AlertHandler's
+// timeout.
+var ErrAlertTimeout = errors.New("alert publish timed out")
+
+// AlertHandler is a slog.Handler that publishes records at or above a level, FATAL by default, to
+// a Publisher in addition to passing every record on to the next handler, so catastrophic errors
+// page someone even when log based alerting lags.
+//
+// Publishing is isolated from logging: it is bounded by a timeout, panics are recovered, and
+// failures are reported to the error handler configured with WithErrorHandler instead of being
+// returned.
+type AlertHandler struct {
+	next      slog.Handler
+	encoder   *Handler
+	publisher Publisher
+	level     slog.Level
+	timeout   time.Duration
+}
+
+// NewAlertHandler creates an AlertHandler that publishes FATAL records to publisher and passes
+// every record on to next. The options configure how published records are encoded.
+func NewAlertHandler(next slog.Handler, publisher Publisher, options ...Option) *AlertHandler {
+	options = append([]Option{WithJSON()}, options...)
+	options = append(options, WithLevel(slog.Level(math.MinInt)))
+
+	return &AlertHandler{
+		next:      next,
+		encoder:   NewHandler(nil, options...),
+		publisher: publisher,
+		level:     slog.LevelError + fatalLevelErrorOffset,
+		timeout:   DefaultAlertTimeout,
+	}
+}
+
+// AtLevel configures the AlertHandler to publish records at or above level.
+func (a *AlertHandler) AtLevel(level slog.Level) *AlertHandler {
+	a.level = level
+	return a
+}
+
+// WithTimeout configures how long the AlertHandler waits for a record to be published.
+func (a *AlertHandler) WithTimeout(timeout time.Duration) *AlertHandler {
+	a.timeout = timeout
+	return a
+}
+
+func (a *AlertHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= a.level || a.next.Enabled(ctx, level)
+}
+
+func (a *AlertHandler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if a.next.Enabled(ctx, record.Level) {
+		err = a.next.Handle(ctx, record)
+	}
+
+	if record.Level >= a.level {
+		if perr := a.publish(ctx, record); perr != nil {
+			a.encoder.reportError(ctx, perr)
+		}
+	}
+
+	return err
+}
+
+func (a *AlertHandler) publish(ctx context.Context, record slog.Record) error {
+	buf := new(bytes.Buffer)
+	if _, err := a.encoder.WithOptions(WithWriter(buf)).emit(ctx, record); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("panic while publishing alert: %v", r)
+			}
+		}()
+		result <- a.publisher.Publish(ctx, bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ErrAlertTimeout
+	}
+}
+
+func (a *AlertHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *a
+	c.next = a.next.WithAttrs(attrs)
+	c.encoder = a.encoder.WithAttrs(attrs).(*Handler)
+	return &c
+}
+
+func (a *AlertHandler) WithGroup(name string) slog.Handler {
+	c := *a
+	c.next = a.next.WithGroup(name)
+	c.encoder = a.encoder.WithGroup(name).(*Handler)
+	return &c
+}
+
+var _ slog.Handler = (*AlertHandler)(nil)
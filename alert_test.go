@@ -0,0 +1,76 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertHandler(t *testing.T) {
+	t.Run("publishes records at the level", func(t *testing.T) {
+		var published [][]byte
+
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewAlertHandler(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), sloglambda.PublisherFunc(func(_ context.Context, message []byte) error {
+			published = append(published, message)
+			return nil
+		})).AtLevel(slog.LevelError)
+		logger := slog.New(handler).With("service", "orders")
+
+		logger.Warn("slow")
+		logger.Error("failed", "orderId", "o-1")
+
+		require.Len(t, published, 1)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(published[0], &record))
+		assert.Equal(t, "failed", record["msg"])
+		assert.Equal(t, "orders", record["service"])
+		assert.Equal(t, "o-1", record["orderId"])
+		assert.Contains(t, buffer.String(), `"msg":"slow"`)
+		assert.Contains(t, buffer.String(), `"msg":"failed"`)
+	})
+
+	t.Run("isolates failures", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			reported []error
+		)
+
+		cases := map[string]sloglambda.PublisherFunc{
+			"timeout": func(ctx context.Context, _ []byte) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			"panic": func(context.Context, []byte) error {
+				panic("boom")
+			},
+		}
+
+		for name, publisher := range cases {
+			t.Run(name, func(t *testing.T) {
+				buffer := new(bytes.Buffer)
+				handler := sloglambda.NewAlertHandler(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), publisher, sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					reported = append(reported, err)
+				})).WithTimeout(10 * time.Millisecond)
+
+				logger := slog.New(handler)
+				logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+				assert.Contains(t, buffer.String(), `"msg":"fatal"`)
+			})
+		}
+
+		require.Len(t, reported, 2)
+		assert.ElementsMatch(t, []string{"alert publish timed out", "panic while publishing alert: boom"}, []string{reported[0].Error(), reported[1].Error()})
+	})
+}
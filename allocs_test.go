@@ -0,0 +1,26 @@
+//go:build !race
+
+package sloglambda_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocs guards against regressions in the number of allocations per record. The race detector
+// changes allocation counts, so it only runs in normal builds.
+func TestAllocs(t *testing.T) {
+	for format, option := range benchmarkFormats {
+		for _, fixture := range benchmarkFixtures {
+			t.Run(format+"/"+fixture.name, func(t *testing.T) {
+				log := fixture.setup(slog.New(sloglambda.NewHandler(io.Discard, option)))
+
+				assert.LessOrEqual(t, testing.AllocsPerRun(100, log), fixture.maxAllocs[format], "allocations per record")
+			})
+		}
+	}
+}
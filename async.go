@@ -0,0 +1,203 @@
+package sloglambda
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BackpressurePolicy determines what an asynchronous Handler does with a record when its queue is
+// full.
+type BackpressurePolicy int
+
+const (
+	// Block waits for room in the queue, up to the timeout configured with WithBlockTimeout.
+	Block BackpressurePolicy = iota
+	// DropOldest discards the oldest queued record to make room for the new one. Pending flushes
+	// (see Handler.EndInvocation) are kept, so they still wait for the records queued before them.
+	DropOldest
+	// DropNewest discards the new record.
+	DropNewest
+)
+
+// ErrQueueFull is reported to the error handler when an asynchronous Handler drops a record
+// because its queue is full.
+var ErrQueueFull = errors.New("queue full")
+
+// WithAsync configures the Handler to write records from a background goroutine, so logging does
+// not wait on a slow writer. Records are formatted by the caller and queued, holding up to
+// queueSize records; policy determines what happens when the queue is full.
+//
+// Dropped records are counted in Stats.QueueDropped and reported to the error handler (see
+// WithErrorHandler). Queued records are written before EndInvocation returns. Close must be called
+// to stop the background goroutine.
+func WithAsync(queueSize int, policy BackpressurePolicy) Option {
+	return func(h *Handler) {
+		if queueSize < 1 {
+			h.invalidOption("WithAsync: queue size must be at least 1")
+			return
+		}
+		h.asyncQueueSize = queueSize
+		h.asyncPolicy = policy
+	}
+}
+
+// WithBlockTimeout configures how long an asynchronous Handler using the Block policy waits for room
+// in the queue before dropping the record. A timeout of zero or less waits indefinitely.
+func WithBlockTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.asyncTimeout = timeout
+	}
+}
+
+type asyncItem struct {
+	ctx   context.Context
+	h     *Handler
+	p     []byte
+	level slog.Level
+	count bool
+	done  chan struct{}
+}
+
+// asyncWriter writes the records queued by a Handler from a background goroutine.
+type asyncWriter struct {
+	policy  BackpressurePolicy
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan asyncItem
+	exited chan struct{}
+}
+
+func newAsyncWriter(h *Handler) *asyncWriter {
+	a := &asyncWriter{
+		policy:  h.asyncPolicy,
+		timeout: h.asyncTimeout,
+		queue:   make(chan asyncItem, h.asyncQueueSize),
+		exited:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.exited)
+
+	for item := range a.queue {
+		if item.h != nil {
+			if _, err := item.h.writeOut(item.ctx, item.p, item.level, item.count); err != nil {
+				item.h.reportError(item.ctx, err)
+			}
+		}
+		if item.done != nil {
+			close(item.done)
+		}
+	}
+}
+
+// enqueue queues a copy of p to be written to the output of h, applying the backpressure policy if
+// the queue is full. Records are counted in the statistics of h when they are written, not when
+// they are queued, so records dropped from the queue are not counted as written.
+func (a *asyncWriter) enqueue(ctx context.Context, h *Handler, p []byte, level slog.Level, count bool) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return 0, ErrWriterClosed
+	}
+
+	item := asyncItem{ctx: context.WithoutCancel(ctx), h: h, p: append([]byte(nil), p...), level: level, count: count}
+
+	select {
+	case a.queue <- item:
+		return len(p), nil
+	default:
+	}
+
+	switch a.policy {
+	case DropOldest:
+		for markers := 0; ; {
+			select {
+			case oldest := <-a.queue:
+				if oldest.done == nil {
+					a.dropped(ctx, oldest.h)
+					break
+				}
+				// Flush markers are never dropped, or the flush would return before the records
+				// queued ahead of it were written. Requeue it and drop the next oldest record, or
+				// wait for room once the queue holds nothing but markers.
+				a.queue <- oldest
+				if markers++; markers >= cap(a.queue) {
+					a.queue <- item
+					return len(p), nil
+				}
+			default:
+			}
+
+			select {
+			case a.queue <- item:
+				return len(p), nil
+			default:
+			}
+		}
+	case Block:
+		var timeout <-chan time.Time
+		if a.timeout > 0 {
+			timer := time.NewTimer(a.timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case a.queue <- item:
+			return len(p), nil
+		case <-timeout:
+		}
+	}
+
+	a.dropped(ctx, h)
+	return 0, ErrQueueFull
+}
+
+func (a *asyncWriter) dropped(ctx context.Context, h *Handler) {
+	h.stats.queueDropped.Add(1)
+	h.reportError(ctx, ErrQueueFull)
+}
+
+// flush waits until the records queued before it was called have been written.
+func (a *asyncWriter) flush() {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return
+	}
+
+	done := make(chan struct{})
+	a.queue <- asyncItem{done: done}
+	<-done
+}
+
+// close writes the queued records and stops the background goroutine.
+func (a *asyncWriter) close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	<-a.exited
+}
+
+// Close writes any queued records, stops the background goroutine of an asynchronous Handler (see
+// WithAsync), then flushes and closes the sink and outputs (see WithOutput). Records logged after
+// Close are not written.
+func (h *Handler) Close() error {
+	if h.async != nil {
+		h.async.close()
+	}
+	return errors.Join(h.flush(context.Background()), h.sink.Close(), h.closeOutputs())
+}
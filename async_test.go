@@ -0,0 +1,153 @@
+package sloglambda_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter blocks every Write until the gate is opened.
+type gatedWriter struct {
+	lineRecorder
+	gate    chan struct{}
+	once    sync.Once
+	started chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+	return w.lineRecorder.Write(p)
+}
+
+func (w *gatedWriter) messages() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var messages []string
+	for _, line := range w.writes {
+		for _, field := range strings.Fields(line) {
+			if msg, ok := strings.CutPrefix(field, "msg="); ok {
+				messages = append(messages, strings.Trim(msg, `"`))
+			}
+		}
+	}
+	return messages
+}
+
+func TestWithAsync(t *testing.T) {
+	cases := map[string]struct {
+		policy   sloglambda.BackpressurePolicy
+		options  []sloglambda.Option
+		expected []string
+	}{
+		"drop newest": {
+			policy:   sloglambda.DropNewest,
+			expected: []string{"first", "second", "third"},
+		},
+		"drop oldest": {
+			policy:   sloglambda.DropOldest,
+			expected: []string{"first", "third", "fourth"},
+		},
+		"block with timeout": {
+			policy:   sloglambda.Block,
+			options:  []sloglambda.Option{sloglambda.WithBlockTimeout(time.Millisecond)},
+			expected: []string{"first", "second", "third"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var reported []error
+
+			out := newGatedWriter()
+			options := append([]sloglambda.Option{
+				sloglambda.WithText(),
+				sloglambda.WithoutTime(),
+				sloglambda.WithAsync(2, tc.policy),
+				sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+					reported = append(reported, err)
+				}),
+			}, tc.options...)
+			handler := sloglambda.NewHandler(out, options...)
+			logger := slog.New(handler)
+
+			logger.Info("first")
+			<-out.started
+
+			logger.Info("second")
+			logger.Info("third")
+			logger.Info("fourth")
+
+			close(out.gate)
+			require.NoError(t, handler.Close())
+
+			assert.Equal(t, tc.expected, out.messages())
+			assert.Equal(t, uint64(1), handler.Stats().QueueDropped)
+			assert.Equal(t, uint64(len(tc.expected)), handler.Stats().Info, "dropped records are not counted as written")
+			require.Len(t, reported, 1)
+			assert.ErrorIs(t, reported[0], sloglambda.ErrQueueFull)
+		})
+	}
+
+	t.Run("drop oldest keeps pending flushes", func(t *testing.T) {
+		out := newGatedWriter()
+		handler := sloglambda.NewHandler(out, sloglambda.WithText(), sloglambda.WithoutTime(), sloglambda.WithAsync(2, sloglambda.DropOldest))
+		logger := slog.New(handler)
+
+		logger.Info("first")
+		<-out.started
+		logger.Info("second")
+
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			_ = handler.EndInvocation(context.Background())
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		logger.Info("third")
+		logger.Info("fourth")
+
+		select {
+		case <-flushed:
+			t.Fatal("EndInvocation returned before the queued records were written")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(out.gate)
+		<-flushed
+		require.NoError(t, handler.Close())
+
+		assert.Equal(t, []string{"first", "fourth"}, out.messages())
+		assert.Equal(t, uint64(2), handler.Stats().QueueDropped)
+		assert.Equal(t, uint64(2), handler.Stats().Info)
+		assert.Equal(t, uint64(len(strings.Join(out.writes, ""))), handler.Stats().BytesWritten)
+	})
+
+	t.Run("end of invocation waits for queued records", func(t *testing.T) {
+		out := new(lineRecorder)
+		handler := sloglambda.NewHandler(out, sloglambda.WithJSON(), sloglambda.WithAsync(16, sloglambda.Block))
+		defer handler.Close()
+
+		logger := slog.New(handler)
+		for range 10 {
+			logger.Info(t.Name())
+		}
+
+		require.NoError(t, handler.EndInvocation(context.Background()))
+		assert.Len(t, out.writes, 10)
+		assert.Equal(t, uint64(10), handler.Stats().Info)
+	})
+}
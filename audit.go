@@ -0,0 +1,144 @@
+package sloglambda
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	kAuditActor    = "actor"
+	kAuditAction   = "action"
+	kAuditResource = "resource"
+	kAuditOutcome  = "outcome"
+	kAuditDetails  = "details"
+	kAuditHash     = "hash"
+	kAuditPrevHash = "prevHash"
+)
+
+// AuditLogType is the default "type" of records written by an Auditor.
+const AuditLogType = "audit.log"
+
+// ErrAuditFieldMissing is returned by Auditor.Audit when a mandatory field is missing or empty.
+var ErrAuditFieldMissing = errors.New("audit field missing")
+
+// AuditActor returns the attribute identifying who performed an audited action.
+func AuditActor(actor string) slog.Attr {
+	return slog.String(kAuditActor, actor)
+}
+
+// AuditResource returns the attribute identifying the resource an audited action was performed on.
+func AuditResource(resource string) slog.Attr {
+	return slog.String(kAuditResource, resource)
+}
+
+// AuditOutcome returns the attribute describing the outcome of an audited action, for example
+// "success" or "denied".
+func AuditOutcome(outcome string) slog.Attr {
+	return slog.String(kAuditOutcome, outcome)
+}
+
+// Auditor writes audit records with a fixed schema, separately from application logs.
+//
+// Every audit record has top-level "actor", "action", "resource", and "outcome" fields, with any
+// other attributes in a "details" group. Audit records are written regardless of the configured
+// level, with the type "audit.log" unless overridden with WithType, and with times in UTC.
+type Auditor struct {
+	handler *Handler
+
+	mu       sync.Mutex
+	chain    bool
+	prevHash string
+}
+
+// NewAuditor creates an Auditor writing to w. The options configure the underlying Handler.
+func NewAuditor(w io.Writer, options ...Option) *Auditor {
+	options = append([]Option{WithType(AuditLogType)}, options...)
+	options = append(options, WithLevel(slog.Level(math.MinInt)), WithUTC())
+
+	return &Auditor{
+		handler: NewHandler(w, options...),
+	}
+}
+
+// HashChain configures the Auditor to chain its records together so tampering can be detected.
+//
+// Each record includes the "hash" of the previous record as "prevHash", and its own "hash": the hex
+// encoded SHA-256 of the previous hash, a newline, and the JSON encoding (with sorted keys) of the
+// record's time, actor, action, resource, outcome, and details.
+func (a *Auditor) HashChain() *Auditor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.chain = true
+	return a
+}
+
+// Audit writes an audit record for the given action.
+//
+// The attributes must include an AuditActor, AuditResource, and AuditOutcome with non-empty values;
+// otherwise an error wrapping ErrAuditFieldMissing is returned and nothing is written.
+func (a *Auditor) Audit(ctx context.Context, action string, attrs ...slog.Attr) error {
+	fields := logRecord{kAuditAction: action}
+	details := make(logRecord)
+
+	for _, attr := range attrs {
+		switch attr.Key {
+		case kAuditActor, kAuditResource, kAuditOutcome:
+			fields[attr.Key] = attr.Value.Resolve().String()
+		default:
+			details.appendWith(attr, &a.handler.format)
+		}
+	}
+
+	for _, key := range []string{kAuditActor, kAuditAction, kAuditResource, kAuditOutcome} {
+		if value, _ := fields[key].(string); value == "" {
+			return fmt.Errorf("%w: %s", ErrAuditFieldMissing, key)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "audit", 0)
+	for _, key := range []string{kAuditActor, kAuditAction, kAuditResource, kAuditOutcome} {
+		record.AddAttrs(slog.String(key, fields[key].(string)))
+	}
+	if len(details) > 0 {
+		record.AddAttrs(slog.Any(kAuditDetails, details))
+		fields[kAuditDetails] = details
+	}
+
+	hash := ""
+	if a.chain {
+		fields[slog.TimeKey] = a.handler.format.time(record.Time).Format(time.RFC3339Nano)
+
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(append([]byte(a.prevHash+"\n"), payload...))
+		hash = hex.EncodeToString(sum[:])
+
+		record.AddAttrs(slog.String(kAuditPrevHash, a.prevHash), slog.String(kAuditHash, hash))
+	}
+
+	// The chain only advances past records that were written, so a failed write does not leave a
+	// gap between the hashes of the records around it.
+	if err := a.handler.Handle(ctx, record); err != nil {
+		return err
+	}
+	if a.chain {
+		a.prevHash = hash
+	}
+	return nil
+}
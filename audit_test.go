@@ -0,0 +1,137 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor(t *testing.T) {
+	t.Run("writes the audit schema", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		auditor := sloglambda.NewAuditor(buffer, sloglambda.WithJSON())
+
+		err := auditor.Audit(context.Background(), "order.refund",
+			sloglambda.AuditActor("alice"),
+			sloglambda.AuditResource("order/123"),
+			sloglambda.AuditOutcome("success"),
+			slog.Int("amount", 42),
+		)
+		require.NoError(t, err)
+
+		var result map[string]any
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &result))
+
+		assert.Equal(t, "audit.log", result["type"])
+		assert.Equal(t, "alice", result["actor"])
+		assert.Equal(t, "order.refund", result["action"])
+		assert.Equal(t, "order/123", result["resource"])
+		assert.Equal(t, "success", result["outcome"])
+		assert.Equal(t, map[string]any{"amount": float64(42)}, result["details"])
+		assert.NotContains(t, result, "hash")
+	})
+
+	t.Run("validates mandatory fields", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		auditor := sloglambda.NewAuditor(buffer, sloglambda.WithJSON())
+
+		err := auditor.Audit(context.Background(), "order.refund", sloglambda.AuditActor("alice"), sloglambda.AuditOutcome("success"))
+
+		assert.ErrorIs(t, err, sloglambda.ErrAuditFieldMissing)
+		assert.ErrorContains(t, err, "resource")
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("is written regardless of level", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		auditor := sloglambda.NewAuditor(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelError))
+
+		err := auditor.Audit(context.Background(), "login", sloglambda.AuditActor("alice"), sloglambda.AuditResource("session"), sloglambda.AuditOutcome("success"))
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, buffer.String())
+	})
+
+	t.Run("chains hashes", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		auditor := sloglambda.NewAuditor(buffer, sloglambda.WithJSON()).HashChain()
+
+		for _, outcome := range []string{"denied", "success"} {
+			err := auditor.Audit(context.Background(), "login", sloglambda.AuditActor("alice"), sloglambda.AuditResource("session"), sloglambda.AuditOutcome(outcome))
+			require.NoError(t, err)
+		}
+
+		decoder := json.NewDecoder(buffer)
+		prevHash := ""
+		for decoder.More() {
+			var result map[string]any
+			require.NoError(t, decoder.Decode(&result))
+
+			assert.Equal(t, prevHash, result["prevHash"])
+
+			payload, err := json.Marshal(map[string]any{
+				"action":   result["action"],
+				"actor":    result["actor"],
+				"outcome":  result["outcome"],
+				"resource": result["resource"],
+				"time":     result["time"],
+			})
+			require.NoError(t, err)
+
+			sum := sha256.Sum256(append([]byte(prevHash+"\n"), payload...))
+			assert.Equal(t, hex.EncodeToString(sum[:]), result["hash"])
+
+			prevHash = result["hash"].(string)
+		}
+		assert.NotEmpty(t, prevHash)
+	})
+
+	t.Run("does not advance the chain when the write fails", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		writer := &toggleWriter{w: buffer}
+		auditor := sloglambda.NewAuditor(writer, sloglambda.WithJSON()).HashChain()
+
+		audit := func(outcome string) error {
+			return auditor.Audit(context.Background(), "login", sloglambda.AuditActor("alice"), sloglambda.AuditResource("session"), sloglambda.AuditOutcome(outcome))
+		}
+
+		require.NoError(t, audit("denied"))
+
+		writer.fail = true
+		assert.Error(t, audit("denied"))
+
+		writer.fail = false
+		require.NoError(t, audit("success"))
+
+		decoder := json.NewDecoder(buffer)
+		var first, second map[string]any
+		require.NoError(t, decoder.Decode(&first))
+		require.NoError(t, decoder.Decode(&second))
+		assert.False(t, decoder.More())
+
+		assert.Equal(t, first["hash"], second["prevHash"])
+	})
+}
+
+// toggleWriter writes to w unless fail is set.
+type toggleWriter struct {
+	w    io.Writer
+	fail bool
+}
+
+func (w *toggleWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("unavailable")
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,111 @@
+package sloglambda
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrWriterClosed is returned when writing to a closed BatchWriter.
+var ErrWriterClosed = errors.New("writer closed")
+
+// BatchWriter is an io.Writer that coalesces the records written by a Handler into fewer, larger
+// writes to the underlying writer.
+//
+// Buffered records are written once they reach the batch size, when the flush interval elapses
+// after the first buffered record, when the Handler ends an invocation (see
+// Handler.EndInvocation), and on Flush or Close. Each Write is assumed to be a complete record and
+// is never split across writes to the underlying writer.
+type BatchWriter struct {
+	w        io.Writer
+	size     int
+	interval time.Duration
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	timer  *time.Timer
+	closed bool
+}
+
+// NewBatchWriter creates a BatchWriter that writes to w in batches of up to size bytes, flushing at
+// least every interval. An interval of zero or less disables the periodic flush.
+func NewBatchWriter(w io.Writer, size int, interval time.Duration) *BatchWriter {
+	b := &BatchWriter{
+		w:        w,
+		size:     size,
+		interval: interval,
+	}
+	b.buf.Grow(size)
+	return b
+}
+
+// Write implements io.Writer.
+func (b *BatchWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if b.buf.Len() > 0 && b.buf.Len()+len(p) > b.size {
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(p) >= b.size {
+		return b.w.Write(p)
+	}
+
+	b.buf.Write(p)
+	if b.interval > 0 && b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, func() {
+			_ = b.Flush()
+		})
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (b *BatchWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush()
+}
+
+// Close flushes the buffered records and stops the periodic flush. Writes after Close return
+// ErrWriterClosed.
+func (b *BatchWriter) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	return b.flush()
+}
+
+func (b *BatchWriter) flush() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if b.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := b.w.Write(b.buf.Bytes())
+	b.buf.Reset()
+	return err
+}
+
+var _ io.WriteCloser = (*BatchWriter)(nil)
+
+// flusher is implemented by writers that buffer records, such as BatchWriter.
+type flusher interface {
+	Flush() error
+}
@@ -0,0 +1,71 @@
+package sloglambda_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchWriter(t *testing.T) {
+	t.Run("flushes at the batch size", func(t *testing.T) {
+		out := new(lineRecorder)
+		w := sloglambda.NewBatchWriter(out, 10, 0)
+
+		for _, record := range []string{"aaaa\n", "bbbb\n", "cccc\n", strings.Repeat("d", 20) + "\n"} {
+			_, err := w.Write([]byte(record))
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"aaaa\nbbbb\n", "cccc\n", strings.Repeat("d", 20) + "\n"}, out.writes)
+	})
+
+	t.Run("flushes after the interval", func(t *testing.T) {
+		out := new(lineRecorder)
+		w := sloglambda.NewBatchWriter(out, 1024, 10*time.Millisecond)
+		defer w.Close()
+
+		_, err := w.Write([]byte("record\n"))
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			out.mu.Lock()
+			defer out.mu.Unlock()
+			return len(out.writes) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("flushes at the end of the invocation", func(t *testing.T) {
+		out := new(lineRecorder)
+		w := sloglambda.NewBatchWriter(out, 1<<20, 0)
+		handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+		logger := slog.New(handler)
+
+		logger.Info("one")
+		logger.Info("two")
+		assert.Empty(t, out.writes)
+
+		require.NoError(t, handler.EndInvocation(context.Background()))
+		require.Len(t, out.writes, 1)
+		assert.Equal(t, 2, strings.Count(out.writes[0], "\n"))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		out := new(lineRecorder)
+		w := sloglambda.NewBatchWriter(out, 1024, time.Hour)
+
+		_, err := w.Write([]byte("record\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, []string{"record\n"}, out.writes)
+
+		_, err = w.Write([]byte("late\n"))
+		assert.ErrorIs(t, err, sloglambda.ErrWriterClosed)
+	})
+}
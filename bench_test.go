@@ -0,0 +1,107 @@
+package sloglambda_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+)
+
+// benchmarkFixture is a representative logging call, used by the benchmarks and the allocation
+// regression tests.
+type benchmarkFixture struct {
+	name string
+	// maxAllocs is the number of allocations a record may take, by format, before TestAllocs fails.
+	// The limits leave some headroom over the measured allocations.
+	maxAllocs map[string]float64
+	// setup returns the function logging one record with logger.
+	setup func(logger *slog.Logger) func()
+}
+
+var benchmarkFixtures = []benchmarkFixture{
+	{
+		name:      "small record",
+		maxAllocs: map[string]float64{"JSON": 38, "Text": 54},
+		setup: func(logger *slog.Logger) func() {
+			return func() {
+				logger.Info("request handled", "status", 200)
+			}
+		},
+	},
+	{
+		name:      "20 attrs",
+		maxAllocs: map[string]float64{"JSON": 188, "Text": 193},
+		setup: func(logger *slog.Logger) func() {
+			attrs := make([]slog.Attr, 0, 20)
+			for i := range 5 {
+				attrs = append(attrs,
+					slog.String(fmt.Sprintf("string%d", i), "value"),
+					slog.Int(fmt.Sprintf("int%d", i), i),
+					slog.Bool(fmt.Sprintf("bool%d", i), i%2 == 0),
+					slog.Duration(fmt.Sprintf("duration%d", i), time.Duration(i)*time.Millisecond),
+				)
+			}
+			return func() {
+				logger.LogAttrs(context.Background(), slog.LevelInfo, "many attributes", attrs...)
+			}
+		},
+	},
+	{
+		name:      "deep groups",
+		maxAllocs: map[string]float64{"JSON": 70, "Text": 85},
+		setup: func(logger *slog.Logger) func() {
+			logger = logger.WithGroup("a").WithGroup("b").WithGroup("c")
+			return func() {
+				logger.Info("nested", slog.Group("d", slog.Group("e", slog.Int("depth", 5))))
+			}
+		},
+	},
+	{
+		name:      "error with stack",
+		maxAllocs: map[string]float64{"JSON": 66, "Text": 89},
+		setup: func(logger *slog.Logger) func() {
+			err := fmt.Errorf("loading order: %w", errors.New("connection reset"))
+			panicAttr := sloglambda.Panic(err)
+			return func() {
+				logger.Error("request failed", "error", err, panicAttr)
+			}
+		},
+	},
+	{
+		name:      "preloaded attrs",
+		maxAllocs: map[string]float64{"JSON": 54, "Text": 80},
+		setup: func(logger *slog.Logger) func() {
+			logger = logger.With("service", "orders", "region", "us-east-1", "version", 3, "canary", false)
+			return func() {
+				logger.Info("request handled", "status", 200)
+			}
+		},
+	},
+}
+
+var benchmarkFormats = map[string]sloglambda.Option{
+	"JSON": sloglambda.WithJSON(),
+	"Text": sloglambda.WithText(),
+}
+
+func BenchmarkFixtures(b *testing.B) {
+	for format, option := range benchmarkFormats {
+		for _, fixture := range benchmarkFixtures {
+			b.Run(format+"/"+fixture.name, func(b *testing.B) {
+				log := fixture.setup(slog.New(sloglambda.NewHandler(io.Discard, option)))
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					log()
+				}
+			})
+		}
+	}
+}
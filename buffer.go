@@ -0,0 +1,79 @@
+package sloglambda
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	defaultBufferSize        = 1 << 10
+	defaultMaxRetainedBuffer = 16 << 10
+)
+
+// bufferPool reuses the buffers records are encoded into.
+type bufferPool struct {
+	pool        sync.Pool
+	initialSize int
+	maxRetained int
+	disabled    bool
+}
+
+func newBufferPool(initialSize, maxRetained int) *bufferPool {
+	p := &bufferPool{
+		initialSize: initialSize,
+		maxRetained: maxRetained,
+	}
+	p.pool.New = p.new
+	return p
+}
+
+// defaultBufferPool is shared by Handlers that do not configure their own pool.
+var defaultBufferPool = newBufferPool(defaultBufferSize, defaultMaxRetainedBuffer)
+
+func (p *bufferPool) new() any {
+	b := bytes.NewBuffer(nil)
+	b.Grow(p.initialSize)
+	return b
+}
+
+func (p *bufferPool) get() *bytes.Buffer {
+	if p.disabled {
+		return p.new().(*bytes.Buffer)
+	}
+	return p.pool.Get().(*bytes.Buffer)
+}
+
+func (p *bufferPool) put(b *bytes.Buffer) {
+	if p.retains(b) {
+		b.Reset()
+		p.pool.Put(b)
+	}
+}
+
+// retains reports whether the buffer should be returned to the pool.
+func (p *bufferPool) retains(b *bytes.Buffer) bool {
+	return !p.disabled && (p.maxRetained == 0 || b.Cap() <= p.maxRetained)
+}
+
+// WithBufferPool configures the pool of buffers the Handler encodes records into.
+//
+// Buffers start with initialSize bytes of capacity, and buffers that grew beyond maxRetained bytes
+// are released instead of being returned to the pool. A maxRetained of zero retains every buffer.
+// The defaults are 1KB and 16KB; functions that routinely log large records should raise
+// maxRetained so their buffers are reused.
+func WithBufferPool(initialSize, maxRetained int) Option {
+	return func(h *Handler) {
+		if initialSize < 0 || maxRetained < 0 {
+			h.invalidOption("WithBufferPool: negative size")
+			return
+		}
+		h.buffers = newBufferPool(initialSize, maxRetained)
+	}
+}
+
+// WithoutBufferPool configures the Handler to allocate a new buffer for every record.
+func WithoutBufferPool() Option {
+	return func(h *Handler) {
+		h.buffers = &bufferPool{disabled: true, initialSize: defaultBufferSize}
+	}
+}
@@ -0,0 +1,32 @@
+package sloglambda
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bufferPool(t *testing.T) {
+	t.Run("initial size", func(t *testing.T) {
+		p := newBufferPool(4096, 0)
+		assert.GreaterOrEqual(t, p.get().Cap(), 4096)
+	})
+
+	t.Run("retains buffers up to the maximum", func(t *testing.T) {
+		p := newBufferPool(0, 64)
+
+		assert.True(t, p.retains(bytes.NewBuffer(make([]byte, 0, 64))))
+		assert.False(t, p.retains(bytes.NewBuffer(make([]byte, 0, 65))))
+	})
+
+	t.Run("without a maximum", func(t *testing.T) {
+		p := newBufferPool(0, 0)
+		assert.True(t, p.retains(bytes.NewBuffer(make([]byte, 0, 1<<20))))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p := &bufferPool{disabled: true}
+		assert.False(t, p.retains(new(bytes.Buffer)))
+	})
+}
@@ -0,0 +1,44 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+var kBuildInfo = "build"
+
+var readBuildInfo = sync.OnceValue(func() slog.Attr {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return slog.Attr{}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("goVersion", info.GoVersion),
+		slog.String("module", info.Main.Path),
+		slog.String("version", info.Main.Version),
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			attrs = append(attrs, slog.String("revision", setting.Value))
+		case "vcs.modified":
+			attrs = append(attrs, slog.Bool("dirty", setting.Value == "true"))
+		}
+	}
+
+	return slog.Attr{Key: kBuildInfo, Value: slog.GroupValue(attrs...)}
+})
+
+// WithBuildInfo configures the Handler to include a "build" group identifying the running binary.
+//
+// The group contains the Go version, main module path and version, and when available the VCS
+// revision and whether the working tree was modified. The build information is read once per
+// process.
+func WithBuildInfo() Option {
+	return func(h *Handler) {
+		h.buildInfo = readBuildInfo()
+	}
+}
@@ -0,0 +1,27 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithBuildInfo()))
+
+	logger.Info(t.Name())
+
+	var result struct {
+		Build map[string]any `json:"build"`
+	}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &result))
+
+	assert.Equal(t, runtime.Version(), result.Build["goVersion"])
+}
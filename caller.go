@@ -0,0 +1,90 @@
+package sloglambda
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+var (
+	kCallerAccount        = "callerAccount"
+	kClaimedCallerAccount = "claimedCallerAccount"
+)
+
+// clientContextCallerAccount is the key of the Lambda client context custom field written as
+// "claimedCallerAccount".
+const clientContextCallerAccount = "callerAccount"
+
+type callerAccountKey struct{}
+
+// WithCallerAccount configures the Handler to include the AWS account ID of the caller of the
+// invocation, for functions invoked from other accounts through a resource-based policy.
+//
+// The account stored in the context by ContextWithCallerAccount, which the function is responsible
+// for establishing (see CallerAccountFromEvent), is written as a top-level "callerAccount" field.
+// The "callerAccount" custom field of the Lambda client context is set by the invoker and not
+// authenticated, so it is written separately, as "claimedCallerAccount".
+func WithCallerAccount() Option {
+	return func(h *Handler) {
+		h.callerAccount = true
+	}
+}
+
+// ContextWithCallerAccount returns a copy of ctx carrying the AWS account ID of the caller of the
+// invocation. Records logged with the returned context by a Handler configured with
+// WithCallerAccount include it as a top-level "callerAccount" field.
+func ContextWithCallerAccount(ctx context.Context, accountID string) context.Context {
+	return context.WithValue(ctx, callerAccountKey{}, accountID)
+}
+
+// CallerAccountFromContext returns the caller account ID stored in ctx by ContextWithCallerAccount.
+func CallerAccountFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	accountID, ok := ctx.Value(callerAccountKey{}).(string)
+	return accountID, ok && accountID != ""
+}
+
+// claimedCallerAccount returns the "callerAccount" custom field of the client context the function
+// was invoked with.
+func claimedCallerAccount(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if lc, _ := lambdacontext.FromContext(ctx); lc != nil {
+		return lc.ClientContext.Custom[clientContextCallerAccount]
+	}
+	return ""
+}
+
+// callerAccountEventPaths are the fields of the events known to carry the account of the caller, in
+// the order they are tried.
+var callerAccountEventPaths = []string{
+	"requestContext.identity.accountId",       // API Gateway REST API with IAM authorization
+	"requestContext.authorizer.iam.accountId", // API Gateway HTTP API and function URLs with IAM authorization
+	"account", // EventBridge
+}
+
+// CallerAccountFromEvent extracts the account ID of the caller from a JSON encoded event. It
+// recognizes API Gateway and function URL requests authorized with IAM, and EventBridge events.
+func CallerAccountFromEvent(event []byte) (string, bool) {
+	var value map[string]any
+	if err := json.Unmarshal(event, &value); err != nil {
+		return "", false
+	}
+
+	for _, path := range callerAccountEventPaths {
+		object := value
+		keys := strings.Split(path, ".")
+		for _, key := range keys[:len(keys)-1] {
+			object, _ = object[key].(map[string]any)
+		}
+		if accountID, ok := object[keys[len(keys)-1]].(string); ok && accountID != "" {
+			return accountID, true
+		}
+	}
+	return "", false
+}
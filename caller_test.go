@@ -0,0 +1,83 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCallerAccount(t *testing.T) {
+	t.Run("from the context", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithCallerAccount()))
+
+		ctx := sloglambda.ContextWithCallerAccount(context.Background(), "210987654321")
+		logger.InfoContext(ctx, t.Name())
+
+		assert.Contains(t, buffer.String(), `"callerAccount":"210987654321"`)
+	})
+
+	t.Run("claimed by the client context", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithCallerAccount()))
+
+		lc := &lambdacontext.LambdaContext{AwsRequestID: "caller-123"}
+		lc.ClientContext.Custom = map[string]string{"callerAccount": "111122223333"}
+		logger.InfoContext(lambdacontext.NewContext(context.Background(), lc), t.Name())
+
+		assert.Contains(t, buffer.String(), `"claimedCallerAccount":"111122223333"`)
+		assert.NotContains(t, buffer.String(), `"callerAccount"`)
+	})
+
+	t.Run("omitted when unknown", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithCallerAccount()))
+
+		logger.InfoContext(lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "caller-456"}), t.Name())
+
+		assert.NotContains(t, buffer.String(), `"callerAccount"`)
+		assert.NotContains(t, buffer.String(), `"claimedCallerAccount"`)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		ctx := sloglambda.ContextWithCallerAccount(context.Background(), "210987654321")
+		logger.InfoContext(ctx, t.Name())
+
+		assert.NotContains(t, buffer.String(), `"callerAccount"`)
+	})
+
+	t.Run("JSONSchema", func(t *testing.T) {
+		schema, err := sloglambda.NewHandler(io.Discard, sloglambda.WithCallerAccount()).JSONSchema()
+		require.NoError(t, err)
+
+		assert.Contains(t, string(schema), `"callerAccount"`)
+		assert.Contains(t, string(schema), `"claimedCallerAccount"`)
+	})
+}
+
+func TestCallerAccountFromEvent(t *testing.T) {
+	cases := map[string]string{
+		`{"requestContext":{"identity":{"accountId":"111111111111"}}}`:           "111111111111",
+		`{"requestContext":{"authorizer":{"iam":{"accountId":"222222222222"}}}}`: "222222222222",
+		`{"source":"orders","account":"333333333333","detail":{}}`:               "333333333333",
+		`{"requestContext":{"identity":{"sourceIp":"10.0.0.1"}}}`:                "",
+		`[]`:       "",
+		`not json`: "",
+	}
+
+	for event, want := range cases {
+		accountID, ok := sloglambda.CallerAccountFromEvent([]byte(event))
+		assert.Equal(t, want, accountID, event)
+		assert.Equal(t, want != "", ok, event)
+	}
+}
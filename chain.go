@@ -0,0 +1,31 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Decorator wraps a slog.Handler with additional behavior, such as filtering or sampling.
+//
+// A decorator's handler must implement WithAttrs and WithGroup by wrapping the result of the same
+// call on the handler it decorates, so attributes and groups propagate through a chain.
+type Decorator func(next slog.Handler) slog.Handler
+
+// Chain wraps base with the given decorators.
+//
+// The first decorator is the outermost: a record passes through the decorators in the order they
+// are given before it reaches base.
+func Chain(base slog.Handler, decorators ...Decorator) slog.Handler {
+	h := base
+	for i := len(decorators) - 1; i >= 0; i-- {
+		h = decorators[i](h)
+	}
+	return h
+}
+
+// FilterDecorator returns a Decorator that applies Filter with the given predicate.
+func FilterDecorator(keep func(ctx context.Context, record slog.Record) bool) Decorator {
+	return func(next slog.Handler) slog.Handler {
+		return Filter(next, keep)
+	}
+}
@@ -0,0 +1,39 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+
+	trace := func(name string) sloglambda.Decorator {
+		return sloglambda.FilterDecorator(func(context.Context, slog.Record) bool {
+			order = append(order, name)
+			return true
+		})
+	}
+
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.Chain(sloglambda.NewHandler(buffer, sloglambda.WithJSON()),
+		trace("first"),
+		trace("second"),
+		sloglambda.FilterDecorator(func(_ context.Context, r slog.Record) bool {
+			return r.Message != "drop"
+		}),
+	)
+	logger := slog.New(handler).With("a", 1).WithGroup("g")
+
+	logger.Info("keep", "b", 2)
+	logger.Info("drop")
+
+	assert.Equal(t, []string{"first", "second", "first", "second"}, order)
+	assert.Contains(t, buffer.String(), `"a":1,"g":{"b":2}`)
+	assert.NotContains(t, buffer.String(), `"msg":"drop"`)
+}
@@ -0,0 +1,126 @@
+package sloglambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// CollisionPolicy determines what the Handler does when a group or attribute has the same name as a
+// field already in the record, such as the "record" field holding the Lambda context or the
+// "source" field.
+type CollisionPolicy int
+
+const (
+	// CollisionOverwrite replaces the existing field. This is the default.
+	CollisionOverwrite CollisionPolicy = iota
+	// CollisionRename adds the group or attribute under its name with a "_" suffix, for example
+	// "record_".
+	CollisionRename
+	// CollisionMerge adds the group's attributes to the existing field when both are groups, and
+	// otherwise renames like CollisionRename.
+	CollisionMerge
+	// CollisionError drops the record and returns an error from Handle, which is also reported to
+	// the error handler (see WithErrorHandler).
+	CollisionError
+)
+
+// ErrKeyCollision is wrapped by the errors reported for key collisions.
+var ErrKeyCollision = errors.New("key collision")
+
+// WithGroupCollisionPolicy configures how the Handler resolves a group name that collides with an
+// existing field in the record.
+func WithGroupCollisionPolicy(policy CollisionPolicy) Option {
+	return func(h *Handler) {
+		h.groupCollisions = policy
+	}
+}
+
+// WithReservedKeyPolicy configures how the Handler resolves a top level attribute whose key
+// collides with a field the Handler writes itself, such as "record", "type", or "source".
+//
+// With CollisionRename, and with CollisionMerge for attributes that are not groups, the attribute
+// is renamed and the collision is reported to the error handler (see WithErrorHandler).
+func WithReservedKeyPolicy(policy CollisionPolicy) Option {
+	return func(h *Handler) {
+		h.reservedPolicy = policy
+	}
+}
+
+// reservedKeys returns the fields the Handler wrote to the record, which attributes may not
+// replace, or nil if attributes may overwrite them.
+func (h *Handler) reservedKeys(record logRecord) map[string]struct{} {
+	if h.reservedPolicy == CollisionOverwrite {
+		return nil
+	}
+
+	reserved := make(map[string]struct{}, len(record))
+	for key := range record {
+		reserved[key] = struct{}{}
+	}
+	return reserved
+}
+
+// reserveAttr applies the reserved key policy to an attribute being added to value.
+func (h *Handler) reserveAttr(ctx context.Context, value logRecord, reserved map[string]struct{}, a slog.Attr) (slog.Attr, error) {
+	if _, ok := reserved[a.Key]; !ok {
+		return a, nil
+	}
+	if _, ok := a.Value.Any().(logTypeOverride); ok {
+		return a, nil
+	}
+
+	a.Value = a.Value.Resolve()
+
+	switch h.reservedPolicy {
+	case CollisionMerge:
+		if _, ok := value[a.Key].(logRecord); ok && a.Value.Kind() == slog.KindGroup {
+			return a, nil
+		}
+	case CollisionError:
+		err := fmt.Errorf("%w: attribute %q collides with a field written by the Handler", ErrKeyCollision, a.Key)
+		h.reportError(ctx, err)
+		return a, err
+	}
+
+	renamed := renameKey(value, a.Key)
+	h.reportError(ctx, fmt.Errorf("%w: attribute %q collides with a field written by the Handler, renamed to %q", ErrKeyCollision, a.Key, renamed))
+	a.Key = renamed
+
+	return a, nil
+}
+
+// openGroup returns the group named key in value, creating it according to the collision policy if
+// value already has a field with that name.
+func (h *Handler) openGroup(value logRecord, key string) (logRecord, error) {
+	existing, ok := value[key]
+	if !ok || h.groupCollisions == CollisionOverwrite {
+		group := make(logRecord, 10)
+		value[key] = group
+		return group, nil
+	}
+
+	switch h.groupCollisions {
+	case CollisionMerge:
+		if group, ok := existing.(logRecord); ok {
+			return group, nil
+		}
+	case CollisionError:
+		return nil, fmt.Errorf("%w: group %q collides with an existing field", ErrKeyCollision, key)
+	}
+
+	group := make(logRecord, 10)
+	value[renameKey(value, key)] = group
+	return group, nil
+}
+
+// renameKey returns key with enough "_" suffixes to not collide with a field in value.
+func renameKey(value logRecord, key string) string {
+	for {
+		key += "_"
+		if _, ok := value[key]; !ok {
+			return key
+		}
+	}
+}
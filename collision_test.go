@@ -0,0 +1,100 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithGroupCollisionPolicy(t *testing.T) {
+	cases := map[string]struct {
+		policy   sloglambda.CollisionPolicy
+		expected string
+	}{
+		"overwrite": {
+			policy:   sloglambda.CollisionOverwrite,
+			expected: `{"level":"INFO","msg":"hello","record":{"id":1},"type":"app.log"}`,
+		},
+		"rename": {
+			policy:   sloglambda.CollisionRename,
+			expected: `{"level":"INFO","msg":"hello","record":{"functionName":"test-function","version":"$LATEST"},"record_":{"id":1},"type":"app.log"}`,
+		},
+		"merge": {
+			policy:   sloglambda.CollisionMerge,
+			expected: `{"level":"INFO","msg":"hello","record":{"functionName":"test-function","id":1,"version":"$LATEST"},"type":"app.log"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithGroupCollisionPolicy(tc.policy)))
+
+			logger.WithGroup("record").Info("hello", "id", 1)
+
+			assert.JSONEq(t, tc.expected, buffer.String())
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		var reported error
+
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithGroupCollisionPolicy(sloglambda.CollisionError), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			reported = err
+		}))
+
+		record := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+		record.AddAttrs(slog.Int("id", 1))
+
+		err := handler.WithGroup("msg").Handle(context.Background(), record)
+		require.ErrorIs(t, err, sloglambda.ErrKeyCollision)
+		assert.Equal(t, err, reported)
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestWithReservedKeyPolicy(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		var reported []error
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithReservedKeyPolicy(sloglambda.CollisionRename), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			reported = append(reported, err)
+		})))
+
+		logger.With("type", "order").Info("hello", "record", "r-1", sloglambda.Type("audit.log"), slog.Group("g", "record", 1))
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"hello","record":{"functionName":"test-function","version":"$LATEST"},"record_":"r-1","type":"audit.log","type_":"order","g":{"record":1}}`, buffer.String())
+		require.Len(t, reported, 2)
+		assert.ErrorIs(t, reported[0], sloglambda.ErrKeyCollision)
+		assert.ErrorContains(t, reported[1], `attribute "record" collides with a field written by the Handler, renamed to "record_"`)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithReservedKeyPolicy(sloglambda.CollisionMerge)))
+
+		logger.Info("hello", slog.Group("record", "id", 1), "msg", "other")
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"hello","msg_":"other","record":{"functionName":"test-function","id":1,"version":"$LATEST"},"type":"app.log"}`, buffer.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithReservedKeyPolicy(sloglambda.CollisionError))
+
+		record := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+		record.AddAttrs(slog.String("level", "custom"))
+
+		err := handler.Handle(context.Background(), record)
+		assert.ErrorIs(t, err, sloglambda.ErrKeyCollision)
+		assert.Empty(t, buffer.String())
+	})
+}
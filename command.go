@@ -0,0 +1,122 @@
+package sloglambda
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	kCommandStream = "stream"
+	kCommandPID    = "pid"
+	kCommand       = "command"
+)
+
+// Command runs an exec.Cmd with its standard output and standard error captured, emitting each
+// line the process writes as a record through a slog.Handler, so the output of CLI tools a function
+// shells out to is structured like the rest of its logs.
+//
+// Each record carries a "stream" field ("stdout" or "stderr"), the "pid" of the process, and the
+// "command": the base name of the executable, without its arguments, which may hold secrets. Lines
+// are framed as by LogWriter.
+type Command struct {
+	ctx         context.Context
+	cmd         *exec.Cmd
+	handler     slog.Handler
+	stdoutLevel slog.Level
+	stderrLevel slog.Level
+	options     []LogWriterOption
+
+	wg      sync.WaitGroup
+	writers []*LogWriter
+}
+
+// CaptureCommand creates a Command that runs cmd, emitting its output through h with ctx, so the
+// records of a command run during an invocation carry its Lambda context. Lines written to standard
+// output are emitted at INFO and lines written to standard error at WARN. The Stdout and Stderr
+// fields of cmd must be nil.
+func CaptureCommand(ctx context.Context, cmd *exec.Cmd, h slog.Handler) *Command {
+	return &Command{
+		ctx:         ctx,
+		cmd:         cmd,
+		handler:     h,
+		stdoutLevel: slog.LevelInfo,
+		stderrLevel: slog.LevelWarn,
+	}
+}
+
+// WithLevels configures the levels lines written to standard output and standard error are
+// emitted at.
+func (c *Command) WithLevels(stdout, stderr slog.Level) *Command {
+	c.stdoutLevel = stdout
+	c.stderrLevel = stderr
+	return c
+}
+
+// WithOptions configures the options of the LogWriters the lines are emitted with, such as
+// DetectLevel.
+func (c *Command) WithOptions(options ...LogWriterOption) *Command {
+	c.options = options
+	return c
+}
+
+// Run starts the command and waits for it to complete.
+func (c *Command) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Start starts the command without waiting for it to complete.
+func (c *Command) Start() error {
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := c.cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := c.cmd.Start(); err != nil {
+		return err
+	}
+
+	h := c.handler.WithAttrs([]slog.Attr{
+		slog.Int(kCommandPID, c.cmd.Process.Pid),
+		slog.String(kCommand, filepath.Base(c.cmd.Path)),
+	})
+	c.capture(stdout, h, "stdout", c.stdoutLevel)
+	c.capture(stderr, h, "stderr", c.stderrLevel)
+
+	return nil
+}
+
+func (c *Command) capture(r io.Reader, h slog.Handler, stream string, level slog.Level) {
+	options := append([]LogWriterOption{LogWriterContext(c.ctx)}, c.options...)
+	w := NewLogWriter(h.WithAttrs([]slog.Attr{slog.String(kCommandStream, stream)}), level, options...)
+	c.writers = append(c.writers, w)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		_, _ = io.Copy(w, r)
+	}()
+}
+
+// Wait waits for the command to exit and for its output to be emitted, including a final line
+// without a trailing newline.
+func (c *Command) Wait() error {
+	c.wg.Wait()
+
+	errs := []error{c.cmd.Wait()}
+	for _, w := range c.writers {
+		errs = append(errs, w.Flush())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("emits each line with the stream, pid, and command", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		cmd := exec.Command("sh", "-c", `echo "first"; echo "problem" >&2; printf "last"`)
+
+		require.NoError(t, sloglambda.CaptureCommand(context.Background(), cmd, sloglambda.NewHandler(buffer, sloglambda.WithJSON())).Run())
+
+		records := map[string]map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			records[record["msg"].(string)] = record
+		}
+		require.Len(t, records, 3)
+
+		assert.Equal(t, "INFO", records["first"]["level"])
+		assert.Equal(t, "stdout", records["first"]["stream"])
+		assert.Equal(t, "sh", records["first"]["command"])
+		assert.Equal(t, float64(cmd.Process.Pid), records["first"]["pid"])
+
+		assert.Equal(t, "WARN", records["problem"]["level"])
+		assert.Equal(t, "stderr", records["problem"]["stream"])
+
+		assert.Equal(t, "stdout", records["last"]["stream"])
+	})
+
+	t.Run("levels and options", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		cmd := exec.Command("sh", "-c", `echo "ERROR: broken" >&2; echo "progress" >&2`)
+
+		err := sloglambda.CaptureCommand(context.Background(), cmd, sloglambda.NewHandler(buffer, sloglambda.WithJSON())).
+			WithLevels(slog.LevelInfo, slog.LevelInfo).
+			WithOptions(sloglambda.DetectLevel()).
+			Run()
+		require.NoError(t, err)
+
+		assert.Contains(t, buffer.String(), `"level":"ERROR","msg":"broken"`)
+		assert.Contains(t, buffer.String(), `"level":"INFO","msg":"progress"`)
+	})
+
+	t.Run("emits with the context", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "cmd-123"})
+		cmd := exec.Command("sh", "-c", `echo "inside"`)
+
+		require.NoError(t, sloglambda.CaptureCommand(ctx, cmd, sloglambda.NewHandler(buffer, sloglambda.WithJSON())).Run())
+
+		assert.Contains(t, buffer.String(), `"requestId":"cmd-123"`)
+	})
+
+	t.Run("returns the exit error", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		cmd := exec.Command("sh", "-c", `echo "exiting"; exit 3`)
+
+		err := sloglambda.CaptureCommand(context.Background(), cmd, sloglambda.NewHandler(buffer)).Run()
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 3, exitErr.ExitCode())
+		assert.Contains(t, buffer.String(), "exiting")
+	})
+}
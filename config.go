@@ -0,0 +1,203 @@
+package sloglambda
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	envLogType   = "SLOG_LAMBDA_TYPE"
+	envLogSource = "SLOG_LAMBDA_SOURCE"
+)
+
+// Format is the output format of a Handler.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+
+	// FormatMsgpack is the format of a Handler configured with WithMsgpack.
+	FormatMsgpack Format = "msgpack"
+	// FormatCustom is the format of a Handler configured with an Encoder other than those provided
+	// by the package (see WithEncoder).
+	FormatCustom Format = "custom"
+)
+
+// Config is the Handler configuration read from the environment.
+type Config struct {
+	// Level is read from AWS_LAMBDA_LOG_LEVEL, defaulting to INFO.
+	Level slog.Level
+	// Format is read from AWS_LAMBDA_LOG_FORMAT, defaulting to text.
+	Format Format
+	// Type is read from SLOG_LAMBDA_TYPE, defaulting to "app.log".
+	Type string
+	// Source is read from SLOG_LAMBDA_SOURCE, defaulting to false.
+	Source bool
+}
+
+// ConfigFromEnv reads the Handler configuration from the environment variables NewHandler uses.
+//
+// Unlike NewHandler, which falls back to the default for values it does not understand,
+// ConfigFromEnv returns an error describing every invalid value alongside a Config containing the
+// defaults for those values.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Level:  slog.LevelInfo,
+		Format: FormatText,
+		Type:   "app.log",
+	}
+
+	var errs []error
+
+	if value := os.Getenv(lambdaEnvLogLevel); value != "" {
+		level, err := ParseLevel(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", lambdaEnvLogLevel, err))
+		} else {
+			config.Level = level
+		}
+	}
+
+	if value := os.Getenv(lambdaEnvLogFormat); value != "" {
+		format, err := ParseFormat(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", lambdaEnvLogFormat, err))
+		} else {
+			config.Format = format
+		}
+	}
+
+	if value, ok := os.LookupEnv(envLogType); ok {
+		config.Type = strings.TrimSpace(value)
+	}
+
+	if value := strings.TrimSpace(os.Getenv(envLogSource)); value != "" {
+		source, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q", envLogSource, value))
+		} else {
+			config.Source = source
+		}
+	}
+
+	return config, errors.Join(errs...)
+}
+
+// Options returns the Options that configure a Handler to match the Config.
+func (c Config) Options() []Option {
+	options := []Option{
+		WithLevel(c.Level),
+		WithType(c.Type),
+	}
+
+	if c.Format == FormatJSON {
+		options = append(options, WithJSON())
+	} else {
+		options = append(options, WithText())
+	}
+
+	if c.Source {
+		options = append(options, WithSource())
+	}
+
+	return options
+}
+
+// ParseFormat parses a log format as accepted by AWS_LAMBDA_LOG_FORMAT. Valid values are "json" and
+// "text", in any case.
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(format))); f {
+	case FormatJSON, FormatText:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q", format)
+	}
+}
+
+// ParseLevel parses a log level as accepted by AWS_LAMBDA_LOG_LEVEL. Valid values are "TRACE",
+// "DEBUG", "INFO", "WARN", "ERROR", and "FATAL", in any case.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "trace":
+		return slog.LevelDebug - traceLevelDebugOffset, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "fatal":
+		return slog.LevelError + fatalLevelErrorOffset, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q", level)
+	}
+}
+
+// Keys are the names of the fields a Handler writes for the parts of every record.
+type Keys struct {
+	Level   string
+	Message string
+	Time    string
+	Type    string
+	Record  string
+}
+
+// Level returns the minimum level of records the Handler currently writes.
+func (h *Handler) Level() slog.Level {
+	return h.level.Level()
+}
+
+// Format returns the output format of the Handler, as determined by its encoder.
+func (h *Handler) Format() Format {
+	switch h.recordEncoder().(type) {
+	case JSONEncoder:
+		return FormatJSON
+	case TextEncoder:
+		return FormatText
+	case MsgpackEncoder:
+		return FormatMsgpack
+	default:
+		return FormatCustom
+	}
+}
+
+// Type returns the value of the Handler's "type" field.
+func (h *Handler) Type() string {
+	return h.logType
+}
+
+// Source reports whether the Handler includes source code information in records.
+func (h *Handler) Source() bool {
+	return h.source
+}
+
+// Keys returns the names of the fields the Handler writes.
+func (h *Handler) Keys() Keys {
+	keys := Keys{
+		Level:   slog.LevelKey,
+		Message: h.messageKey(),
+		Type:    kLambdaLogType,
+		Record:  kLambdaRecord,
+	}
+	if !h.excludeTime {
+		keys.Time = h.timeKey()
+	}
+	return keys
+}
+
+// Config returns the effective configuration of the Handler.
+func (h *Handler) Config() Config {
+	return Config{
+		Level:  h.Level(),
+		Format: h.Format(),
+		Type:   h.Type(),
+		Source: h.Source(),
+	}
+}
@@ -0,0 +1,134 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("with valid values", func(t *testing.T) {
+		t.Setenv("AWS_LAMBDA_LOG_LEVEL", "debug")
+		t.Setenv("AWS_LAMBDA_LOG_FORMAT", "JSON")
+		t.Setenv("SLOG_LAMBDA_TYPE", "audit.log")
+		t.Setenv("SLOG_LAMBDA_SOURCE", "true")
+
+		config, err := sloglambda.ConfigFromEnv()
+		require.NoError(t, err)
+
+		assert.Equal(t, sloglambda.Config{
+			Level:  slog.LevelDebug,
+			Format: sloglambda.FormatJSON,
+			Type:   "audit.log",
+			Source: true,
+		}, config)
+	})
+
+	t.Run("with invalid values", func(t *testing.T) {
+		t.Setenv("AWS_LAMBDA_LOG_LEVEL", "verbose")
+		t.Setenv("AWS_LAMBDA_LOG_FORMAT", "yaml")
+		t.Setenv("SLOG_LAMBDA_SOURCE", "sometimes")
+
+		config, err := sloglambda.ConfigFromEnv()
+		assert.ErrorContains(t, err, `AWS_LAMBDA_LOG_LEVEL: invalid log level "verbose"`)
+		assert.ErrorContains(t, err, `AWS_LAMBDA_LOG_FORMAT: invalid log format "yaml"`)
+		assert.ErrorContains(t, err, `SLOG_LAMBDA_SOURCE: invalid value "sometimes"`)
+
+		assert.Equal(t, sloglambda.Config{
+			Level:  slog.LevelInfo,
+			Format: sloglambda.FormatText,
+			Type:   "app.log",
+		}, config)
+	})
+
+	t.Run("configures NewHandler", func(t *testing.T) {
+		t.Setenv("SLOG_LAMBDA_TYPE", "audit.log")
+
+		buffer := new(bytes.Buffer)
+		slog.New(sloglambda.NewHandler(buffer, sloglambda.WithoutTime())).Info(t.Name())
+
+		assert.Contains(t, buffer.String(), `"type":"audit.log"`)
+	})
+}
+
+func TestConfig_Options(t *testing.T) {
+	config := sloglambda.Config{Level: slog.LevelWarn, Format: sloglambda.FormatJSON, Type: "custom.log"}
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, append(config.Options(), sloglambda.WithoutTime())...))
+	logger.Info("skipped")
+	logger.Warn(t.Name())
+
+	assert.JSONEq(t, `{"level":"WARN","msg":"TestConfig_Options","record":{"functionName":"test-function","version":"$LATEST"},"type":"custom.log"}`, buffer.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := sloglambda.ParseLevel(" Fatal ")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelError+4, level)
+
+	_, err = sloglambda.ParseLevel("")
+	assert.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := sloglambda.ParseFormat("Text")
+	require.NoError(t, err)
+	assert.Equal(t, sloglambda.FormatText, format)
+
+	_, err = sloglambda.ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestHandler_Config(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_LOG_LEVEL", "error")
+	t.Setenv("SLOG_LAMBDA_SOURCE", "1")
+
+	h := sloglambda.NewHandler(new(bytes.Buffer))
+
+	assert.Equal(t, slog.LevelError, h.Level())
+	assert.Equal(t, sloglambda.FormatJSON, h.Format())
+	assert.Equal(t, "app.log", h.Type())
+	assert.True(t, h.Source())
+
+	assert.Equal(t, sloglambda.Config{
+		Level:  slog.LevelError,
+		Format: sloglambda.FormatJSON,
+		Type:   "app.log",
+		Source: true,
+	}, h.Config())
+
+	t.Run("Format", func(t *testing.T) {
+		cases := map[sloglambda.Format]sloglambda.Option{
+			sloglambda.FormatJSON:    sloglambda.WithJSON(),
+			sloglambda.FormatText:    sloglambda.WithText(),
+			sloglambda.FormatMsgpack: sloglambda.WithMsgpack(),
+			sloglambda.FormatCustom:  sloglambda.WithEncoder(csvEncoder{}),
+		}
+		for format, option := range cases {
+			assert.Equal(t, format, sloglambda.NewHandler(new(bytes.Buffer), option).Format())
+		}
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		assert.Equal(t, sloglambda.Keys{
+			Level:   "level",
+			Message: "msg",
+			Time:    "time",
+			Type:    "type",
+			Record:  "record",
+		}, h.Keys())
+
+		insights := sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithInsightsFields(), sloglambda.WithoutTime())
+		assert.Equal(t, sloglambda.Keys{
+			Level:   "level",
+			Message: "@message",
+			Type:    "type",
+			Record:  "record",
+		}, insights.Keys())
+	})
+}
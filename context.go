@@ -0,0 +1,98 @@
+package sloglambda
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying the given logger.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger stored in ctx by ContextWithLogger, or slog.Default if ctx
+// does not carry a logger.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+var (
+	kRequestID  = "requestId"
+	kRequestSeq = "requestSeq"
+)
+
+var requestSeq atomic.Uint64
+
+// NewRequestLogger returns a child of base for a single request, with a "requestId" attribute
+// and a "requestSeq" attribute numbering the requests handled by the process.
+//
+// The request ID is the Lambda request ID when ctx carries a Lambda context, otherwise a randomly
+// generated UUID, so local runs and test harnesses can still correlate the records of a request.
+// If base is nil, the logger from ctx is used (see LoggerFromContext).
+func NewRequestLogger(ctx context.Context, base *slog.Logger) *slog.Logger {
+	if base == nil {
+		base = LoggerFromContext(ctx)
+	}
+
+	requestID := requestIDFromContext(ctx)
+	if requestID == "" {
+		requestID = newUUID()
+	}
+
+	return base.With(
+		slog.String(kRequestID, requestID),
+		slog.Uint64(kRequestSeq, requestSeq.Add(1)),
+	)
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+var kContextError = "ctxErr"
+
+// WithContextError configures the Handler to add a "ctxErr" field to records logged with a context
+// that is already done: "canceled" when it was canceled, or "deadline_exceeded" when its deadline
+// passed. Records logged during shutdown or while racing a timeout can then be told apart from
+// records on the normal path.
+func WithContextError() Option {
+	return func(h *Handler) {
+		h.contextError = true
+	}
+}
+
+// contextError returns the value of the "ctxErr" field for ctx, or an empty string if ctx is not
+// done.
+func contextError(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	switch err := ctx.Err(); {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,75 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestLogger(t *testing.T) {
+	type entry struct {
+		RequestID  string `json:"requestId"`
+		RequestSeq uint64 `json:"requestSeq"`
+	}
+
+	buffer := new(bytes.Buffer)
+	base := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	t.Run("with a Lambda context", func(t *testing.T) {
+		buffer.Reset()
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+
+		sloglambda.NewRequestLogger(ctx, base).Info(t.Name())
+
+		var e entry
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &e))
+		assert.Equal(t, "req-1", e.RequestID)
+	})
+
+	t.Run("without a Lambda context", func(t *testing.T) {
+		buffer.Reset()
+		ctx := sloglambda.ContextWithLogger(context.Background(), base)
+
+		sloglambda.NewRequestLogger(ctx, nil).Info(t.Name())
+		var e1 entry
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &e1))
+
+		buffer.Reset()
+		sloglambda.NewRequestLogger(ctx, nil).Info(t.Name())
+		var e2 entry
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &e2))
+
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), e1.RequestID)
+		assert.NotEqual(t, e1.RequestID, e2.RequestID)
+		assert.Equal(t, e1.RequestSeq+1, e2.RequestSeq)
+	})
+}
+
+func TestWithContextError(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithContextError()))
+
+	logger.InfoContext(context.Background(), "running")
+	assert.NotContains(t, buffer.String(), "ctxErr")
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	buffer.Reset()
+	logger.InfoContext(canceled, "canceled")
+	assert.Contains(t, buffer.String(), `"ctxErr":"canceled"`)
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	buffer.Reset()
+	logger.InfoContext(expired, "expired")
+	assert.Contains(t, buffer.String(), `"ctxErr":"deadline_exceeded"`)
+}
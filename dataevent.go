@@ -0,0 +1,118 @@
+package sloglambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+var (
+	kDataEventCategory     = "eventCategory"
+	kDataEventName         = "eventName"
+	kDataEventSource       = "eventSource"
+	kDataEventIdentity     = "userIdentity"
+	kDataEventIdentityARN  = "arn"
+	kDataEventResources    = "resources"
+	kDataEventReadOnly     = "readOnly"
+	kDataEventOutcome      = "outcome"
+	kDataEventErrorCode    = "errorCode"
+	kDataEventErrorMessage = "errorMessage"
+)
+
+// DataEventLogType is the "type" of records written by DataEvent.
+const DataEventLogType = "data.event"
+
+// Outcomes of a DataEvent.
+const (
+	DataEventSuccess = "Success"
+	DataEventFailure = "Failure"
+)
+
+// ErrDataEventFieldMissing is returned by DataEvent.Log when a mandatory field is empty.
+var ErrDataEventFieldMissing = errors.New("data event field missing")
+
+// DataEvent is a record describing an access to data, using the vocabulary of CloudTrail data
+// events so security tooling can consume application level access logs alongside CloudTrail.
+//
+// The record uses the "data.event" type, with top level "eventCategory" ("Data"), "eventName",
+// "eventSource", "userIdentity", "resources", "readOnly", and "outcome" fields, and "errorCode"
+// and "errorMessage" when set. Failed accesses are logged at WARN, everything else at INFO.
+type DataEvent struct {
+	Principal    string // the ARN of the principal, written as userIdentity.arn
+	Action       string // the eventName, for example "GetOrder"
+	Resource     string // the ARN of the resource accessed
+	ResourceType string // the type of the resource, for example "Custom::Order"
+	ReadOnly     bool
+	Outcome      string // DataEventSuccess or DataEventFailure
+	ErrorCode    string
+	ErrorMessage string
+
+	// Source is the eventSource. It defaults to the name of the function.
+	Source string
+}
+
+// Level returns the level the data event is written at, based on its outcome.
+func (e DataEvent) Level() slog.Level {
+	if e.Outcome == DataEventFailure {
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+// Validate returns an error wrapping ErrDataEventFieldMissing if the principal, action, resource,
+// or outcome is empty.
+func (e DataEvent) Validate() error {
+	for _, field := range []struct{ name, value string }{
+		{"Principal", e.Principal},
+		{"Action", e.Action},
+		{"Resource", e.Resource},
+		{"Outcome", e.Outcome},
+	} {
+		if field.value == "" {
+			return fmt.Errorf("%w: %s", ErrDataEventFieldMissing, field.name)
+		}
+	}
+	return nil
+}
+
+// Attrs returns the attributes of the data event.
+func (e DataEvent) Attrs() []slog.Attr {
+	source := e.Source
+	if source == "" {
+		source = os.Getenv(lambdaEnvFunctionName)
+	}
+
+	resource := map[string]string{"ARN": e.Resource}
+	if e.ResourceType != "" {
+		resource["type"] = e.ResourceType
+	}
+
+	attrs := []slog.Attr{
+		Type(DataEventLogType),
+		slog.String(kDataEventCategory, "Data"),
+		slog.String(kDataEventName, e.Action),
+		slog.String(kDataEventSource, source),
+		slog.Group(kDataEventIdentity, slog.String(kDataEventIdentityARN, e.Principal)),
+		slog.Any(kDataEventResources, []map[string]string{resource}),
+		slog.Bool(kDataEventReadOnly, e.ReadOnly),
+		slog.String(kDataEventOutcome, e.Outcome),
+	}
+	if e.ErrorCode != "" {
+		attrs = append(attrs, slog.String(kDataEventErrorCode, e.ErrorCode))
+	}
+	if e.ErrorMessage != "" {
+		attrs = append(attrs, slog.String(kDataEventErrorMessage, e.ErrorMessage))
+	}
+	return attrs
+}
+
+// Log writes the data event to logger, or returns the error from Validate without writing it.
+func (e DataEvent) Log(ctx context.Context, logger *slog.Logger) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	logger.LogAttrs(ctx, e.Level(), "data event", e.Attrs()...)
+	return nil
+}
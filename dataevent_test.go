@@ -0,0 +1,75 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataEvent(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime()))
+
+	t.Run("success", func(t *testing.T) {
+		buffer.Reset()
+		err := sloglambda.DataEvent{
+			Principal:    "arn:aws:iam::123456789012:role/reader",
+			Action:       "GetOrder",
+			Resource:     "arn:aws:dynamodb:us-east-1:123456789012:table/orders",
+			ResourceType: "AWS::DynamoDB::Table",
+			ReadOnly:     true,
+			Outcome:      sloglambda.DataEventSuccess,
+		}.Log(context.Background(), logger)
+		require.NoError(t, err)
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+		delete(record, "record")
+
+		assert.Equal(t, map[string]any{
+			"level":         "INFO",
+			"msg":           "data event",
+			"type":          "data.event",
+			"eventCategory": "Data",
+			"eventName":     "GetOrder",
+			"eventSource":   "test-function",
+			"userIdentity":  map[string]any{"arn": "arn:aws:iam::123456789012:role/reader"},
+			"resources":     []any{map[string]any{"ARN": "arn:aws:dynamodb:us-east-1:123456789012:table/orders", "type": "AWS::DynamoDB::Table"}},
+			"readOnly":      true,
+			"outcome":       "Success",
+		}, record)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		buffer.Reset()
+		err := sloglambda.DataEvent{
+			Principal:    "arn:aws:iam::123456789012:user/alice",
+			Action:       "DeleteOrder",
+			Resource:     "arn:aws:dynamodb:us-east-1:123456789012:table/orders",
+			Outcome:      sloglambda.DataEventFailure,
+			ErrorCode:    "AccessDenied",
+			ErrorMessage: "not allowed",
+			Source:       "orders.example.com",
+		}.Log(context.Background(), logger)
+		require.NoError(t, err)
+
+		assert.Contains(t, buffer.String(), `"level":"WARN"`)
+		assert.Contains(t, buffer.String(), `"errorCode":"AccessDenied"`)
+		assert.Contains(t, buffer.String(), `"eventSource":"orders.example.com"`)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		buffer.Reset()
+		err := sloglambda.DataEvent{Principal: "arn:aws:iam::123456789012:user/alice", Action: "GetOrder"}.Log(context.Background(), logger)
+
+		require.ErrorIs(t, err, sloglambda.ErrDataEventFieldMissing)
+		assert.ErrorContains(t, err, "Resource")
+		assert.Empty(t, buffer.String())
+	})
+}
@@ -0,0 +1,110 @@
+package sloglambda
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	kDeadLetterError    = "error"
+	kDeadLetterOriginal = "original"
+	kDeadLetterKeys     = "keys"
+)
+
+// maxDeadLetters bounds the number of records kept by a Handler that failed to encode.
+const maxDeadLetters = 64
+
+// DeadLetter describes a record that failed to encode.
+type DeadLetter struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Keys    []string // the sorted, dot separated paths of the record's fields
+	Err     error
+}
+
+type deadLetters struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (d *deadLetters) add(letter DeadLetter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.letters) >= maxDeadLetters {
+		d.letters = slices.Delete(d.letters, 0, 1)
+	}
+	d.letters = append(d.letters, letter)
+}
+
+// DeadLetters returns the most recent records that failed to encode, oldest first.
+//
+// A record that fails to encode, for example because a value's MarshalJSON method returns an error,
+// is replaced in the output by an ERROR record with the "error", and an "original" group holding the
+// level, message, and field "keys" of the record, so the context of the failure is not lost. The
+// Handler keeps the last 64 of them, shared with the Handlers derived from it.
+func (h *Handler) DeadLetters() []DeadLetter {
+	h.deadLetters.mu.Lock()
+	defer h.deadLetters.mu.Unlock()
+
+	return slices.Clone(h.deadLetters.letters)
+}
+
+// deadLetter records a record that failed to encode, and writes a replacement for it to buf.
+func (h *Handler) deadLetter(buf *bytes.Buffer, record slog.Record, value logRecord, err error) error {
+	fallback := make(logRecord, 8)
+	for _, key := range []string{h.timeKey(), kLambdaRecord, kLambdaLogType, kLambdaRequestId} {
+		if v, ok := value[key]; ok {
+			fallback[key] = v
+		}
+	}
+
+	skip := map[string]bool{slog.LevelKey: true, h.messageKey(): true}
+	for key := range fallback {
+		skip[key] = true
+	}
+
+	var keys []string
+	for key, v := range value {
+		if !skip[key] {
+			keys = appendFieldPaths(keys, key, v)
+		}
+	}
+	slices.Sort(keys)
+
+	h.deadLetters.add(DeadLetter{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+		Keys:    keys,
+		Err:     err,
+	})
+
+	fallback[slog.LevelKey] = lambdaLoggerLevelString(slog.LevelError)
+	fallback[h.messageKey()] = "failed to encode log record"
+	fallback[kDeadLetterError] = err.Error()
+	fallback[kDeadLetterOriginal] = logRecord{
+		slog.LevelKey:   lambdaLoggerLevelString(record.Level),
+		slog.MessageKey: record.Message,
+		kDeadLetterKeys: keys,
+	}
+
+	buf.Reset()
+	return h.encode(buf, fallback)
+}
+
+// appendFieldPaths appends the dot separated paths of the leaf fields of v, found at path.
+func appendFieldPaths(paths []string, path string, v any) []string {
+	group, ok := v.(logRecord)
+	if !ok || len(group) == 0 {
+		return append(paths, path)
+	}
+	for key, v := range group {
+		paths = appendFieldPaths(paths, path+"."+key, v)
+	}
+	return paths
+}
@@ -0,0 +1,48 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New(`cannot "marshal"`)
+}
+
+func TestHandler_DeadLetters(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime())
+	logger := slog.New(handler).With("user", "alice")
+
+	logger.WithGroup("payload").Warn("order placed", "value", []any{failingMarshaler{}}, "id", 1)
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &result), buffer.String())
+	assert.Equal(t, "ERROR", result["level"])
+	assert.Equal(t, "failed to encode log record", result["msg"])
+	assert.Contains(t, result["error"], `cannot "marshal"`)
+	assert.Equal(t, map[string]any{"functionName": "test-function", "version": "$LATEST"}, result["record"])
+	assert.Equal(t, map[string]any{
+		"level": "WARN",
+		"msg":   "order placed",
+		"keys":  []any{"payload.id", "payload.value", "user"},
+	}, result["original"])
+
+	letters := handler.DeadLetters()
+	require.Len(t, letters, 1)
+	assert.Equal(t, slog.LevelWarn, letters[0].Level)
+	assert.Equal(t, "order placed", letters[0].Message)
+	assert.Equal(t, []string{"payload.id", "payload.value", "user"}, letters[0].Keys)
+	assert.Error(t, letters[0].Err)
+
+	assert.Len(t, handler.WithGroup("g").(*sloglambda.Handler).DeadLetters(), 1, "shared with derived handlers")
+}
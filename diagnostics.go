@@ -0,0 +1,116 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+)
+
+var (
+	kDiagnosticsConfig       = "config"
+	kDiagnosticsLevel        = "level"
+	kDiagnosticsLevelSource  = "levelSource"
+	kDiagnosticsFormat       = "format"
+	kDiagnosticsFormatSource = "formatSource"
+	kDiagnosticsType         = "type"
+	kDiagnosticsSource       = "source"
+	kDiagnosticsFeatures     = "features"
+	kDiagnosticsEnvErrors    = "envError"
+)
+
+// Where a setting of the Handler came from, as reported by WithDiagnostics.
+const (
+	settingDefault = "default"
+	settingOption  = "option"
+)
+
+// WithDiagnostics configures the Handler to write a "logging configured" record describing its
+// resolved configuration when it is created.
+//
+// The record has a "config" group with the level and format and where each came from ("option",
+// "default", or the environment variable or LevelSource that provided it), the type, whether source
+// information is included, and the sorted names of the other enabled features. Invalid environment
+// variables (see ConfigFromEnv) are reported as "envError". The record is written regardless of the
+// level.
+func WithDiagnostics() Option {
+	return func(h *Handler) {
+		h.diagnostics = true
+	}
+}
+
+// writeDiagnostics writes the record requested by WithDiagnostics.
+func (h *Handler) writeDiagnostics() {
+	_, envErr := ConfigFromEnv()
+
+	formatSource := settingDefault
+	if h.formatOption != "" {
+		formatSource = settingOption
+	} else if _, err := ParseFormat(os.Getenv(lambdaEnvLogFormat)); err == nil {
+		formatSource = lambdaEnvLogFormat
+	}
+
+	config := []slog.Attr{
+		slog.String(kDiagnosticsLevel, lambdaLoggerLevelString(h.Level())),
+		slog.String(kDiagnosticsLevelSource, h.levelSource),
+		slog.String(kDiagnosticsFormat, string(h.Format())),
+		slog.String(kDiagnosticsFormatSource, formatSource),
+		slog.String(kDiagnosticsType, h.logType),
+		slog.Bool(kDiagnosticsSource, h.source),
+		slog.Any(kDiagnosticsFeatures, h.features()),
+	}
+	if envErr != nil {
+		config = append(config, slog.String(kDiagnosticsEnvErrors, envErr.Error()))
+	}
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "logging configured", 0)
+	record.AddAttrs(slog.Attr{Key: kDiagnosticsConfig, Value: slog.GroupValue(config...)})
+
+	_, _ = h.emit(context.Background(), record)
+}
+
+// features returns the sorted names of the optional features enabled on the Handler.
+func (h *Handler) features() []string {
+	enabled := map[string]bool{
+		"adaptiveLevel":    h.adaptive != nil,
+		"alarmMarker":      h.alarm != nil,
+		"annotations":      h.annotations != nil,
+		"async":            h.async != nil,
+		"budget":           h.budget != nil,
+		"callerAccount":    h.callerAccount,
+		"buildInfo":        h.buildInfo.Key != "",
+		"concurrentWriter": h.concurrentWriter,
+		"contextError":     h.contextError,
+		"deadlineGuard":    h.deadlineGuard > 0,
+		"encoder":          h.encoder != nil,
+		"elapsed":          h.elapsed,
+		"enrichment":       len(h.enrichers) > 0,
+		"goroutineCount":   h.goroutineCount,
+		"goroutineId":      h.goroutineID,
+		"insights":         h.insights,
+		"interceptors":     len(h.interceptors) > 0,
+		"lastError":        h.lastError,
+		"levelValue":       h.levelValue,
+		"levelOverrides":   h.overrides != nil,
+		"memoryStats":      h.memoryStats,
+		"messageSampling":  h.messageSampling != nil,
+		"phase":            h.phase,
+		"schema":           h.schema != nil,
+		"sequence":         h.sequence,
+		"snapStart":        h.snapStart,
+		"suppress":         len(h.suppress) > 0,
+		"tenant":           h.tenantExtractor != nil,
+		"traceContext":     h.traceContext,
+		"withoutTime":      h.excludeTime,
+	}
+
+	features := make([]string, 0, len(enabled))
+	for name, ok := range enabled {
+		if ok {
+			features = append(features, name)
+		}
+	}
+	slices.Sort(features)
+	return features
+}
@@ -0,0 +1,64 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDiagnostics(t *testing.T) {
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]any {
+		t.Helper()
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+		require.Equal(t, "logging configured", out["msg"])
+		return out["config"].(map[string]any)
+	}
+
+	t.Run("from the environment", func(t *testing.T) {
+		var buf bytes.Buffer
+		sloglambda.NewHandler(&buf, sloglambda.WithDiagnostics(), sloglambda.WithSequence())
+
+		assert.Equal(t, map[string]any{
+			"level":        "INFO",
+			"levelSource":  "default",
+			"format":       "json",
+			"formatSource": "AWS_LAMBDA_LOG_FORMAT",
+			"type":         "app.log",
+			"source":       false,
+			"features":     []any{"sequence"},
+		}, decode(t, &buf))
+	})
+
+	t.Run("from options", func(t *testing.T) {
+		var buf bytes.Buffer
+		sloglambda.NewHandler(&buf, sloglambda.WithDiagnostics(), sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelError))
+
+		config := decode(t, &buf)
+		assert.Equal(t, "ERROR", config["level"])
+		assert.Equal(t, "option", config["levelSource"])
+		assert.Equal(t, "option", config["formatSource"])
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		t.Setenv("AWS_LAMBDA_LOG_LEVEL", "loud")
+
+		var buf bytes.Buffer
+		sloglambda.NewHandler(&buf, sloglambda.WithDiagnostics())
+
+		config := decode(t, &buf)
+		assert.Equal(t, "default", config["levelSource"])
+		assert.Contains(t, config["envError"], "AWS_LAMBDA_LOG_LEVEL")
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		sloglambda.NewHandler(&buf)
+		assert.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,93 @@
+package sloglambda
+
+import (
+	"bytes"
+	"slices"
+)
+
+// Fields are the fields of a record, resolved and ready to be encoded.
+//
+// Values are nil, booleans, numbers, strings, time.Time, json.RawMessage, slices, or Fields for
+// groups. Values of other types are those given to the logger, for encoders to format as they see
+// fit.
+type Fields map[string]any
+
+// Encoder writes records in a particular format.
+type Encoder interface {
+	// Encode appends the record to buf. Line based formats end the record with a newline, which the
+	// Handler replaces with its terminator (see WithTerminator).
+	Encode(buf *bytes.Buffer, fields Fields) error
+}
+
+// BinaryEncoder is implemented by encoders of binary formats. Their records are written as they are
+// encoded, without the Handler's terminator (see WithTerminator).
+type BinaryEncoder interface {
+	Encoder
+	Binary()
+}
+
+// JSONEncoder writes records as JSON objects with sorted keys, one per line. It is the encoder
+// configured by WithJSON.
+type JSONEncoder struct{}
+
+// Encode implements Encoder.
+func (JSONEncoder) Encode(buf *bytes.Buffer, fields Fields) error {
+	return encodeJSON(buf, fields)
+}
+
+// TextEncoder writes records as space separated key=value pairs, one per line, with the keys of
+// groups joined by dots. It is the encoder configured by WithText.
+type TextEncoder struct {
+	// Header lists the top level fields written first, in order (see WithTextHeader). The remaining
+	// fields are sorted by key.
+	Header []string
+}
+
+// Encode implements Encoder.
+func (e TextEncoder) Encode(buf *bytes.Buffer, fields Fields) error {
+	start := buf.Len()
+	if err := writeTextRecordWithHeader(buf, fields, "", e.Header); err != nil {
+		return err
+	}
+	// Remove the last trailing space
+	if buf.Len() > start {
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+var (
+	_ Encoder = JSONEncoder{}
+	_ Encoder = TextEncoder{}
+)
+
+// WithEncoder configures the Handler to write records with e, for formats other than JSON and
+// text. It takes precedence over WithJSON, WithText, and the AWS_LAMBDA_LOG_FORMAT environment
+// variable.
+func WithEncoder(e Encoder) Option {
+	return func(h *Handler) {
+		if e == nil {
+			h.invalidOption("WithEncoder: nil encoder")
+			return
+		}
+		h.encoder = e
+	}
+}
+
+// recordEncoder returns the encoder the Handler writes records with.
+func (h *Handler) recordEncoder() Encoder {
+	if h.encoder != nil {
+		return h.encoder
+	}
+	return h.formatEncoder(h.json)
+}
+
+// formatEncoder returns the JSON or text encoder, with the Handler's text header.
+func (h *Handler) formatEncoder(json bool) Encoder {
+	if json {
+		return JSONEncoder{}
+	}
+	return TextEncoder{Header: slices.Clip(h.textHeader)}
+}
@@ -0,0 +1,64 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// csvEncoder writes the level, message, and the sorted top level keys of a record.
+type csvEncoder struct{}
+
+func (csvEncoder) Encode(buf *bytes.Buffer, fields sloglambda.Fields) error {
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	record, ok := fields["record"].(sloglambda.Fields)
+	if !ok {
+		return fmt.Errorf("missing record group")
+	}
+
+	fmt.Fprintf(buf, "%s,%s,%s,%v\n", fields["level"], fields["msg"], record["functionName"], keys)
+	return nil
+}
+
+func TestWithEncoder(t *testing.T) {
+	t.Run("custom encoder", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithEncoder(csvEncoder{}), sloglambda.WithTerminator("\r\n")))
+
+		logger.Info("Hello, world!", "user", "alice")
+
+		assert.Equal(t, "INFO,Hello, world!,test-function,[level msg record type user]\r\n", buffer.String())
+	})
+
+	t.Run("nil encoder", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithEncoder(nil))
+		require.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
+
+func TestJSONEncoder(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	err := sloglambda.JSONEncoder{}.Encode(buffer, sloglambda.Fields{"b": 1, "a": sloglambda.Fields{"c": "<d>"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"c":"\u003cd\u003e"},"b":1}`+"\n", buffer.String())
+}
+
+func TestTextEncoder(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	err := sloglambda.TextEncoder{Header: []string{"msg"}}.Encode(buffer, sloglambda.Fields{"b": 1, "msg": "hi", "a": sloglambda.Fields{"c": "d"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, `msg="hi" a.c="d" b=1`+"\n", buffer.String())
+}
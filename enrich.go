@@ -0,0 +1,76 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+var kStack = "stack"
+
+// Enricher computes additional attributes for a record. The attributes are added to the top level
+// of the record.
+type Enricher func(ctx context.Context, record slog.Record) []slog.Attr
+
+type levelEnricher struct {
+	level     slog.Level
+	enrichers []Enricher
+
+	// fields describes the fields the enrichers add, for JSONSchema, when they are known.
+	fields map[string]FieldType
+}
+
+// WithEnrichment configures the Handler to run the given enrichers only for records at or above
+// level, so expensive attributes are paid for on the records that need them.
+//
+// The option can be given more than once to enrich at different levels.
+func WithEnrichment(level slog.Level, enrichers ...Enricher) Option {
+	return enrichment(level, nil, enrichers...)
+}
+
+// enrichment configures the Handler to run the given enrichers for records at or above level,
+// which add the fields described by fields.
+func enrichment(level slog.Level, fields map[string]FieldType, enrichers ...Enricher) Option {
+	return func(h *Handler) {
+		h.enrichers = append(h.enrichers[:len(h.enrichers):len(h.enrichers)], levelEnricher{
+			level:     level,
+			enrichers: enrichers,
+			fields:    fields,
+		})
+	}
+}
+
+func (h *Handler) enrich(ctx context.Context, record slog.Record, value logRecord) {
+	for _, le := range h.enrichers {
+		if record.Level < le.level {
+			continue
+		}
+		for _, enrich := range le.enrichers {
+			for _, attr := range enrich(ctx, record) {
+				value.appendWith(attr, &h.format)
+			}
+		}
+	}
+}
+
+// StackEnricher returns an Enricher that adds the stack trace of the logging goroutine as a
+// "stack" string.
+func StackEnricher() Enricher {
+	return func(context.Context, slog.Record) []slog.Attr {
+		buf := make([]byte, 4096)
+		for {
+			n := runtime.Stack(buf, false)
+			if n < len(buf) {
+				return []slog.Attr{slog.String(kStack, string(buf[:n]))}
+			}
+			buf = make([]byte, len(buf)*2)
+		}
+	}
+}
+
+// MemoryStatsEnricher returns an Enricher that adds the same "memory" group as WithMemoryStats.
+func MemoryStatsEnricher() Enricher {
+	return func(context.Context, slog.Record) []slog.Attr {
+		return []slog.Attr{memoryStats.attr()}
+	}
+}
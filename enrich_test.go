@@ -0,0 +1,41 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnrichment(t *testing.T) {
+	calls := 0
+	counter := func(context.Context, slog.Record) []slog.Attr {
+		calls++
+		return []slog.Attr{slog.Int("calls", calls)}
+	}
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(),
+		sloglambda.WithEnrichment(slog.LevelError, counter, sloglambda.StackEnricher()),
+		sloglambda.WithEnrichment(slog.LevelWarn, sloglambda.MemoryStatsEnricher()),
+	))
+
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 3)
+
+	assert.NotContains(t, lines[0], `"memory"`)
+	assert.Contains(t, lines[1], `"memory"`)
+	assert.NotContains(t, lines[1], `"stack"`)
+	assert.Contains(t, lines[2], `"calls":1`)
+	assert.Contains(t, lines[2], `"stack":"goroutine `)
+	assert.Equal(t, 1, calls)
+}
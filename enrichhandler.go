@@ -0,0 +1,121 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+)
+
+var kLambdaColdStart = "coldStart"
+
+// Enrich returns a slog.Handler that adds the Lambda "record" group written by a Handler to every
+// record before passing it on to next, so handlers from other packages can be used for encoding.
+//
+// The group holds the function name and version, the initialization type, and, for records logged
+// with a Lambda context, the request ID, the number of invocations the execution environment has
+// served, whether this is its first ("coldStart"), and the "alias" the function was invoked
+// through. The group is always added at the top level of the record, even when groups were opened
+// with WithGroup.
+//
+// Enrich cannot change how next encodes levels; pass ReplaceLevel as the ReplaceAttr function of
+// the slog.HandlerOptions of next to write levels the way a Handler does. Enrich can be used with
+// Chain, as a Decorator.
+func Enrich(next slog.Handler) slog.Handler {
+	return &enrichHandler{next: next}
+}
+
+// ReplaceLevel is a slog.HandlerOptions.ReplaceAttr function that writes levels with the names
+// used by Lambda, including "TRACE" and "FATAL" for levels below DEBUG and above ERROR.
+func ReplaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 || a.Key != slog.LevelKey {
+		return a
+	}
+	if level, ok := a.Value.Any().(slog.Level); ok {
+		a.Value = slog.StringValue(lambdaLoggerLevelString(level))
+	}
+	return a
+}
+
+type enrichHandler struct {
+	next slog.Handler
+	goas []groupOrAttrs
+}
+
+func (e *enrichHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return e.next.Enabled(ctx, level)
+}
+
+func (e *enrichHandler) Handle(ctx context.Context, record slog.Record) error {
+	enriched := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	enriched.AddAttrs(lambdaRecordAttr(ctx))
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	for i := len(e.goas) - 1; i >= 0; i-- {
+		if group := e.goas[i].group; group != "" {
+			if len(attrs) > 0 {
+				attrs = []slog.Attr{{Key: group, Value: slog.GroupValue(attrs...)}}
+			}
+		} else {
+			attrs = append(slices.Clip(e.goas[i].attrs), attrs...)
+		}
+	}
+	enriched.AddAttrs(attrs...)
+
+	return e.next.Handle(ctx, enriched)
+}
+
+func (e *enrichHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return e
+	}
+	return &enrichHandler{next: e.next, goas: append(slices.Clip(e.goas), groupOrAttrs{attrs: attrs})}
+}
+
+func (e *enrichHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return e
+	}
+	return &enrichHandler{next: e.next, goas: append(slices.Clip(e.goas), groupOrAttrs{group: name})}
+}
+
+var _ slog.Handler = (*enrichHandler)(nil)
+
+// lambdaRecordAttr returns the Lambda "record" group for a record logged with ctx.
+func lambdaRecordAttr(ctx context.Context) slog.Attr {
+	return slog.Attr{Key: kLambdaRecord, Value: slog.GroupValue(lambdaRecordAttrs(ctx, true)...)}
+}
+
+// lambdaRecordAttrs returns the fields of the Lambda "record" group for a record logged with ctx,
+// including "coldStart" when coldStart is true.
+func lambdaRecordAttrs(ctx context.Context, coldStart bool) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 6)
+	if value, ok := os.LookupEnv(lambdaEnvFunctionName); ok {
+		attrs = append(attrs, slog.String(kLambdaFunctionName, value))
+	}
+	if value, ok := os.LookupEnv(lambdaEnvFunctionVersion); ok {
+		attrs = append(attrs, slog.String(kLambdaFunctionVersion, value))
+	}
+	if value, ok := os.LookupEnv(lambdaEnvInitializationType); ok {
+		attrs = append(attrs, slog.String(kLambdaInitializationType, value))
+	}
+	if requestID := requestIDFromContext(ctx); requestID != "" {
+		invocation := executionEnvironment.observe(requestID)
+		attrs = append(attrs,
+			slog.String(kLambdaRequestId, requestID),
+			slog.Int64(kLambdaInvocation, invocation),
+		)
+		if coldStart {
+			attrs = append(attrs, slog.Bool(kLambdaColdStart, invocation == 1))
+		}
+	}
+	if alias := aliasFromContext(ctx); alias != "" {
+		attrs = append(attrs, slog.String(kLambdaAlias, alias))
+	}
+	return attrs
+}
@@ -0,0 +1,80 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrich(t *testing.T) {
+	var buf bytes.Buffer
+	next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level:       slog.Level(-8),
+		ReplaceAttr: sloglambda.ReplaceLevel,
+	})
+	logger := slog.New(sloglambda.Enrich(next))
+
+	decode := func(t *testing.T) map[string]any {
+		t.Helper()
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		delete(out, "time")
+		return out
+	}
+
+	t.Run("adds the lambda record", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("Hello, world!", "key", "value")
+
+		assert.Equal(t, map[string]any{
+			"level":  "INFO",
+			"msg":    "Hello, world!",
+			"key":    "value",
+			"record": map[string]any{"functionName": "test-function", "version": "$LATEST"},
+		}, decode(t))
+	})
+
+	t.Run("request ID", func(t *testing.T) {
+		buf.Reset()
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "enrich-1"})
+		logger.InfoContext(ctx, "Hello, world!")
+
+		record := decode(t)["record"].(map[string]any)
+		assert.Equal(t, "enrich-1", record["requestId"])
+		assert.Contains(t, record, "invocation")
+		assert.Contains(t, record, "coldStart")
+	})
+
+	t.Run("record stays at the top level", func(t *testing.T) {
+		buf.Reset()
+		logger.With("a", 1).WithGroup("g").With("b", 2).Info("grouped", "c", 3)
+
+		out := decode(t)
+		assert.Contains(t, out, "record")
+		assert.Equal(t, float64(1), out["a"])
+		assert.Equal(t, map[string]any{"b": float64(2), "c": float64(3)}, out["g"])
+	})
+
+	t.Run("maps levels", func(t *testing.T) {
+		buf.Reset()
+		logger.Log(context.Background(), slog.Level(-8), "trace")
+		assert.Equal(t, "TRACE", decode(t)["level"])
+
+		buf.Reset()
+		logger.Log(context.Background(), slog.Level(12), "fatal")
+		assert.Equal(t, "FATAL", decode(t)["level"])
+	})
+
+	t.Run("empty groups are dropped", func(t *testing.T) {
+		buf.Reset()
+		logger.WithGroup("g").Info("no attrs")
+		assert.NotContains(t, decode(t), "g")
+	})
+}
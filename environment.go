@@ -0,0 +1,131 @@
+package sloglambda
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	kLambdaPhase              = "phase"
+	kLambdaInitializationType = "initializationType"
+	kLambdaInvocation         = "invocation"
+	kSequence                 = "seq"
+)
+
+const (
+	// InitializationOnDemand is the initialization type of an execution environment started in
+	// response to an invocation.
+	InitializationOnDemand = "on-demand"
+	// InitializationProvisionedConcurrency is the initialization type of an execution environment
+	// started ahead of time by provisioned concurrency.
+	InitializationProvisionedConcurrency = "provisioned-concurrency"
+	// InitializationSnapStart is the initialization type of an execution environment restored from
+	// a SnapStart snapshot.
+	InitializationSnapStart = "snap-start"
+)
+
+// InitializationType returns how the current execution environment was initialized, as reported by
+// the AWS_LAMBDA_INITIALIZATION_TYPE environment variable.
+//
+// It returns one of the Initialization constants, or an empty string when not running in Lambda.
+func InitializationType() string {
+	return os.Getenv(lambdaEnvInitializationType)
+}
+
+const (
+	// PhaseInit is the phase of the execution environment before the first invocation.
+	PhaseInit = "init"
+	// PhaseInvoke is the phase of the execution environment once it has served an invocation.
+	PhaseInvoke = "invoke"
+)
+
+// environmentRetainedRequests is the number of request IDs the execution environment remembers the
+// invocation number of.
+const environmentRetainedRequests = 64
+
+// environment tracks the state of the execution environment shared by all Handlers in the process.
+type environment struct {
+	invoked  atomic.Bool
+	sequence atomic.Uint64
+
+	mu          sync.Mutex
+	requests    map[string]int64
+	order       []string
+	invocations int64
+}
+
+var executionEnvironment environment
+
+// observe records that the invocation with the given request ID was served and returns its number
+// among the invocations the execution environment has served.
+//
+// Each request ID is counted once, the first time it is observed, whether by a record or by
+// Handler.EndInvocation. Records of concurrent invocations that interleave are not counted again, and
+// invocations that log nothing are counted when they end.
+func (e *environment) observe(requestID string) int64 {
+	if requestID == "" {
+		return 0
+	}
+	if !e.invoked.Load() {
+		e.invoked.Store(true)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if n, ok := e.requests[requestID]; ok {
+		return n
+	}
+
+	e.invocations++
+	if e.requests == nil {
+		e.requests = make(map[string]int64)
+	}
+	e.requests[requestID] = e.invocations
+	e.order = append(e.order, requestID)
+	if len(e.order) > environmentRetainedRequests {
+		delete(e.requests, e.order[0])
+		e.order = e.order[1:]
+	}
+	return e.invocations
+}
+
+func (e *environment) phase() string {
+	if e.invoked.Load() {
+		return PhaseInvoke
+	}
+	return PhaseInit
+}
+
+// nextSequence returns the next record sequence number of the execution environment.
+func (e *environment) nextSequence() uint64 {
+	return e.sequence.Add(1)
+}
+
+// WithPhase configures the Handler to include the runtime phase in the lambda record.
+//
+// Records handled before the first invocation (during initialization) are tagged with "init", and
+// records handled after any record carrying a Lambda context, or after the first call to
+// Handler.EndInvocation, are tagged with "invoke".
+//
+// The phase is a property of the execution environment, not of the record: a goroutine started
+// during initialization that logs after the first invocation has begun has its records tagged with
+// "invoke", and records logged before the first invocation logs anything are tagged with "init" even
+// if that invocation has already started.
+func WithPhase() Option {
+	return func(h *Handler) {
+		h.phase = true
+	}
+}
+
+// WithSequence configures the Handler to include a "seq" field containing a number that increases
+// monotonically with every record written in the execution environment.
+//
+// The sequence is shared by all Handlers in the process, so records can be totally ordered even when
+// their timestamps collide or CloudWatch delivers them out of order.
+func WithSequence() Option {
+	return func(h *Handler) {
+		h.sequence = true
+	}
+}
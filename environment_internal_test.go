@@ -0,0 +1,72 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPhase(t *testing.T) {
+	invoked := executionEnvironment.invoked.Load()
+	executionEnvironment.invoked.Store(false)
+	t.Cleanup(func() { executionEnvironment.invoked.Store(invoked) })
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(NewHandler(buffer, WithJSON(), WithPhase()))
+
+	logger.Info("init")
+	assert.Contains(t, buffer.String(), `"phase":"init"`)
+	buffer.Reset()
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+	logger.InfoContext(ctx, "invoke")
+	assert.Contains(t, buffer.String(), `"phase":"invoke"`)
+	buffer.Reset()
+
+	logger.Info("after")
+	assert.Contains(t, buffer.String(), `"phase":"invoke"`)
+}
+
+func TestEnvironment_observe(t *testing.T) {
+	env := new(environment)
+
+	assert.Equal(t, int64(0), env.observe(""))
+	assert.Equal(t, int64(1), env.observe("abc-123"))
+	assert.Equal(t, int64(1), env.observe("abc-123"))
+	assert.Equal(t, int64(2), env.observe("def-456"))
+	assert.Equal(t, PhaseInvoke, env.phase())
+}
+
+func TestEnvironment_observeInterleaved(t *testing.T) {
+	env := new(environment)
+
+	assert.Equal(t, int64(1), env.observe("abc-123"))
+	assert.Equal(t, int64(2), env.observe("def-456"))
+	assert.Equal(t, int64(1), env.observe("abc-123"))
+	assert.Equal(t, int64(2), env.observe("def-456"))
+	assert.Equal(t, int64(3), env.observe("ghi-789"))
+}
+
+func TestEnvironment_silentInvocation(t *testing.T) {
+	executionEnvironment.mu.Lock()
+	served := executionEnvironment.invocations
+	executionEnvironment.mu.Unlock()
+
+	buffer := new(bytes.Buffer)
+	handler := NewHandler(buffer, WithJSON())
+
+	silent := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "silent-1"})
+	assert.NoError(t, handler.EndInvocation(silent))
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "loud-1"})
+	slog.New(handler).InfoContext(ctx, "invoked")
+
+	assert.Contains(t, buffer.String(), fmt.Sprintf(`"invocation":%d`, served+2))
+}
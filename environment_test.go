@@ -0,0 +1,61 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializationType(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_INITIALIZATION_TYPE", sloglambda.InitializationProvisionedConcurrency)
+
+	assert.Equal(t, "provisioned-concurrency", sloglambda.InitializationType())
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	logger.Info(t.Name())
+
+	assert.Contains(t, buffer.String(), `"initializationType":"provisioned-concurrency"`)
+}
+
+func TestWithSequence(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSequence()))
+
+	logger.Info("first")
+	logger.Info("second")
+
+	var seq []uint64
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		var result struct {
+			Seq uint64 `json:"seq"`
+		}
+		require.NoError(t, decoder.Decode(&result))
+		seq = append(seq, result.Seq)
+	}
+
+	require.Len(t, seq, 2)
+	assert.Equal(t, seq[0]+1, seq[1])
+}
+
+func TestHandler_invocation(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: t.Name(),
+	})
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText()))
+
+	logger.InfoContext(ctx, t.Name())
+
+	assert.Regexp(t, `record\.invocation=[1-9]\d*`, buffer.String())
+}
@@ -0,0 +1,43 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+)
+
+var kEnvSnapshot = "env"
+
+// WithEnvSnapshot configures the Handler to add an "env" group with the given environment variables
+// to records at ERROR and above (see EnvSnapshotEnricher).
+func WithEnvSnapshot(keys ...string) Option {
+	fields := map[string]FieldType{kEnvSnapshot: FieldObject}
+	for _, key := range keys {
+		fields[kEnvSnapshot+"."+key] = FieldString
+	}
+	return enrichment(slog.LevelError, fields, EnvSnapshotEnricher(keys...))
+}
+
+// EnvSnapshotEnricher returns an Enricher that adds an "env" group holding the values of the given
+// environment variables, such as feature flags or deployment IDs, so errors can be triaged with the
+// context of the deployment without logging the whole environment.
+//
+// The variables are read when the record is logged. Unset variables are omitted, and the group is
+// omitted when none are set.
+func EnvSnapshotEnricher(keys ...string) Enricher {
+	keys = slices.Clone(keys)
+
+	return func(context.Context, slog.Record) []slog.Attr {
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, key := range keys {
+			if value, ok := os.LookupEnv(key); ok {
+				attrs = append(attrs, slog.String(key, value))
+			}
+		}
+		if len(attrs) == 0 {
+			return nil
+		}
+		return []slog.Attr{{Key: kEnvSnapshot, Value: slog.GroupValue(attrs...)}}
+	}
+}
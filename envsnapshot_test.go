@@ -0,0 +1,39 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnvSnapshot(t *testing.T) {
+	t.Setenv("DEPLOY_ID", "d-123")
+	t.Setenv("FEATURE_CHECKOUT", "on")
+	t.Setenv("SECRET_TOKEN", "hunter2")
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithEnvSnapshot("DEPLOY_ID", "FEATURE_CHECKOUT", "UNSET_VARIABLE")))
+
+	logger.Error("failed")
+	logger.Warn("slow")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, map[string]any{"DEPLOY_ID": "d-123", "FEATURE_CHECKOUT": "on"}, record["env"])
+	assert.NotContains(t, lines[0], "hunter2")
+
+	assert.NotContains(t, lines[1], `"env"`)
+}
+
+func TestEnvSnapshotEnricher(t *testing.T) {
+	assert.Nil(t, sloglambda.EnvSnapshotEnricher("UNSET_VARIABLE")(nil, slog.Record{}))
+}
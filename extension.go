@@ -0,0 +1,221 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	lambdaEnvRuntimeAPI = "AWS_LAMBDA_RUNTIME_API"
+
+	extensionAPIVersion = "2020-01-01"
+	extensionNameHeader = "Lambda-Extension-Name"
+	extensionIDHeader   = "Lambda-Extension-Identifier"
+)
+
+var (
+	kShutdownReason = "shutdownReason"
+	kLastRequestID  = "lastRequestId"
+)
+
+// Extensions API events an Extension can register for.
+const (
+	ExtensionEventInvoke   = "INVOKE"
+	ExtensionEventShutdown = "SHUTDOWN"
+)
+
+// ShutdownCanceled is the shutdown reason of the final record written when the context passed to
+// Extension.Run is canceled.
+const ShutdownCanceled = "canceled"
+
+// Extension is a Lambda extension that tracks the invocations of the execution environment through
+// the Extensions API and writes a final record when the environment shuts down, so terminations
+// caused by timeouts or runtime failures leave a record with the last request ID in the logs.
+//
+// The final record is written at INFO with the message "execution environment shutdown", a
+// "shutdownReason" field ("spindown", "timeout", "failure", or "canceled"), and a "lastRequestId"
+// field. It is written at ERROR when the reason is "timeout" or "failure".
+//
+// Lambda only delivers SHUTDOWN events to external extensions. When the Extension runs in the
+// function's process, register it for ExtensionEventInvoke only (see WithEvents) and cancel the
+// context passed to Run on SIGTERM, which Lambda sends to the runtime before shutting down an
+// environment with registered extensions:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+//	defer stop()
+//
+//	ext := sloglambda.NewExtension(handler).WithEvents(sloglambda.ExtensionEventInvoke)
+//	go ext.Run(ctx)
+//
+//	lambda.Start(handle)
+type Extension struct {
+	handler *Handler
+	name    string
+	api     string
+	events  []string
+	client  *http.Client
+
+	mu        sync.Mutex
+	requestID string
+}
+
+// NewExtension creates an Extension that writes its final record with h. It registers with the
+// name of the executable for INVOKE and SHUTDOWN events.
+func NewExtension(h *Handler) *Extension {
+	return &Extension{
+		handler: h,
+		name:    filepath.Base(os.Args[0]),
+		api:     os.Getenv(lambdaEnvRuntimeAPI),
+		events:  []string{ExtensionEventInvoke, ExtensionEventShutdown},
+		client:  http.DefaultClient,
+	}
+}
+
+// WithName configures the name the Extension registers with. For external extensions it must match
+// the file name of the executable in /opt/extensions.
+func (e *Extension) WithName(name string) *Extension {
+	e.name = name
+	return e
+}
+
+// WithEvents configures the events the Extension registers for.
+func (e *Extension) WithEvents(events ...string) *Extension {
+	e.events = events
+	return e
+}
+
+// WithRuntimeAPI configures the host and port of the Extensions API, instead of the value of the
+// AWS_LAMBDA_RUNTIME_API environment variable.
+func (e *Extension) WithRuntimeAPI(api string) *Extension {
+	e.api = api
+	return e
+}
+
+// WithClient configures the http.Client used to call the Extensions API.
+func (e *Extension) WithClient(client *http.Client) *Extension {
+	e.client = client
+	return e
+}
+
+// LastRequestID returns the request ID of the last invocation the Extension was notified of.
+func (e *Extension) LastRequestID() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.requestID
+}
+
+type extensionEvent struct {
+	EventType      string `json:"eventType"`
+	RequestID      string `json:"requestId"`
+	ShutdownReason string `json:"shutdownReason"`
+}
+
+// Run registers the Extension and processes events until the execution environment shuts down or
+// ctx is canceled, writing the final record and flushing the Handler before it returns.
+//
+// Run returns an error when the Extensions API cannot be reached or rejects a request, without
+// writing the final record.
+func (e *Extension) Run(ctx context.Context) error {
+	id, err := e.register(ctx)
+	if err != nil {
+		return e.canceled(ctx, err)
+	}
+
+	for {
+		event, err := e.next(ctx, id)
+		if err != nil {
+			return e.canceled(ctx, err)
+		}
+
+		switch event.EventType {
+		case ExtensionEventInvoke:
+			e.mu.Lock()
+			e.requestID = event.RequestID
+			e.mu.Unlock()
+		case ExtensionEventShutdown:
+			return e.shutdown(ctx, event.ShutdownReason)
+		}
+	}
+}
+
+// canceled writes the final record when err was caused by the cancellation of ctx, and returns err
+// otherwise.
+func (e *Extension) canceled(ctx context.Context, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	return e.shutdown(ctx, ShutdownCanceled)
+}
+
+func (e *Extension) shutdown(ctx context.Context, reason string) error {
+	ctx = context.WithoutCancel(ctx)
+
+	level := slog.LevelInfo
+	if reason == "timeout" || reason == "failure" {
+		level = slog.LevelError
+	}
+
+	slog.New(e.handler).LogAttrs(ctx, level, "execution environment shutdown",
+		slog.String(kShutdownReason, reason),
+		slog.String(kLastRequestID, e.LastRequestID()),
+	)
+	return e.handler.flush(ctx)
+}
+
+func (e *Extension) register(ctx context.Context) (string, error) {
+	body, err := json.Marshal(map[string][]string{"events": e.events})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url("register"), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(extensionNameHeader, e.name)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("extension: unexpected response %s", resp.Status)
+	}
+	return resp.Header.Get(extensionIDHeader), nil
+}
+
+func (e *Extension) next(ctx context.Context, id string) (extensionEvent, error) {
+	var event extensionEvent
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url("event/next"), nil)
+	if err != nil {
+		return event, err
+	}
+	req.Header.Set(extensionIDHeader, id)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return event, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return event, fmt.Errorf("extension: unexpected response %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&event)
+	return event, err
+}
+
+func (e *Extension) url(path string) string {
+	return "http://" + e.api + "/" + extensionAPIVersion + "/extension/" + path
+}
@@ -0,0 +1,109 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extensionAPI serves the Extensions API, returning events in order and blocking once they run out.
+func extensionAPI(t *testing.T, events ...map[string]any) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2020-01-01/extension/register":
+			var body struct {
+				Events []string `json:"events"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			registered = append(registered, r.Header.Get("Lambda-Extension-Name"))
+			registered = append(registered, body.Events...)
+			w.Header().Set("Lambda-Extension-Identifier", "ext-id")
+		case "/2020-01-01/extension/event/next":
+			if r.Header.Get("Lambda-Extension-Identifier") != "ext-id" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if len(events) == 0 {
+				<-r.Context().Done()
+				return
+			}
+			_ = json.NewEncoder(w).Encode(events[0])
+			events = events[1:]
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &registered
+}
+
+func TestExtension(t *testing.T) {
+	t.Run("writes a final record at shutdown", func(t *testing.T) {
+		server, registered := extensionAPI(t,
+			map[string]any{"eventType": "INVOKE", "requestId": "req-1"},
+			map[string]any{"eventType": "INVOKE", "requestId": "req-2"},
+			map[string]any{"eventType": "SHUTDOWN", "shutdownReason": "timeout"},
+		)
+
+		buffer := new(bytes.Buffer)
+		ext := sloglambda.NewExtension(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime())).
+			WithName("logger").
+			WithRuntimeAPI(strings.TrimPrefix(server.URL, "http://"))
+
+		require.NoError(t, ext.Run(context.Background()))
+
+		assert.Equal(t, []string{"logger", "INVOKE", "SHUTDOWN"}, *registered)
+		assert.Equal(t, "req-2", ext.LastRequestID())
+		assert.Equal(t, `{"lastRequestId":"req-2","level":"ERROR","msg":"execution environment shutdown","record":{"functionName":"test-function","version":"$LATEST"},"shutdownReason":"timeout","type":"app.log"}`+"\n", buffer.String())
+	})
+
+	t.Run("writes a final record when canceled", func(t *testing.T) {
+		server, registered := extensionAPI(t,
+			map[string]any{"eventType": "INVOKE", "requestId": "req-1"},
+		)
+
+		buffer := new(bytes.Buffer)
+		ext := sloglambda.NewExtension(sloglambda.NewHandler(buffer, sloglambda.WithJSON())).
+			WithEvents(sloglambda.ExtensionEventInvoke).
+			WithRuntimeAPI(strings.TrimPrefix(server.URL, "http://"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- ext.Run(ctx) }()
+
+		require.Eventually(t, func() bool { return ext.LastRequestID() == "req-1" }, time.Second, time.Millisecond)
+		cancel()
+
+		require.NoError(t, <-done)
+		assert.Contains(t, *registered, "INVOKE")
+		assert.NotContains(t, *registered, "SHUTDOWN")
+		assert.Contains(t, buffer.String(), `"level":"INFO"`)
+		assert.Contains(t, buffer.String(), `"shutdownReason":"canceled"`)
+		assert.Contains(t, buffer.String(), `"lastRequestId":"req-1"`)
+	})
+
+	t.Run("registration errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		t.Cleanup(server.Close)
+
+		buffer := new(bytes.Buffer)
+		ext := sloglambda.NewExtension(sloglambda.NewHandler(buffer)).WithRuntimeAPI(strings.TrimPrefix(server.URL, "http://"))
+
+		assert.ErrorContains(t, ext.Run(context.Background()), "403")
+		assert.Empty(t, buffer.String())
+	})
+}
@@ -0,0 +1,47 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Filter returns a slog.Handler that passes a record on to h only when keep returns true.
+//
+// The predicate receives the record as it was logged, including its own attributes but not those
+// added with WithAttrs. Use it to drop records by message, attribute value, or context state in one
+// place, for example health check access logs. If keep is nil, h is returned unchanged.
+func Filter(h slog.Handler, keep func(ctx context.Context, record slog.Record) bool) slog.Handler {
+	if keep == nil {
+		return h
+	}
+	return &filterHandler{
+		next: h,
+		keep: keep,
+	}
+}
+
+type filterHandler struct {
+	next slog.Handler
+	keep func(context.Context, slog.Record) bool
+}
+
+func (f *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return f.next.Enabled(ctx, level)
+}
+
+func (f *filterHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !f.keep(ctx, record) {
+		return nil
+	}
+	return f.next.Handle(ctx, record)
+}
+
+func (f *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &filterHandler{next: f.next.WithAttrs(attrs), keep: f.keep}
+}
+
+func (f *filterHandler) WithGroup(name string) slog.Handler {
+	return &filterHandler{next: f.next.WithGroup(name), keep: f.keep}
+}
+
+var _ slog.Handler = (*filterHandler)(nil)
@@ -0,0 +1,39 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.Filter(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), func(_ context.Context, r slog.Record) bool {
+		return !strings.HasPrefix(r.Message, "health")
+	})).With("component", "test")
+
+	logger.Info("health check")
+	logger.Info("order placed")
+
+	assert.NotContains(t, buffer.String(), `"msg":"health check"`)
+	assert.Contains(t, buffer.String(), `"msg":"order placed"`)
+	assert.Contains(t, buffer.String(), `"component":"test"`)
+}
+
+func TestFilter_nilPredicate(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON())
+
+	filtered := sloglambda.Filter(handler, nil)
+	assert.Same(t, handler, filtered)
+
+	assert.NotPanics(t, func() {
+		slog.New(filtered).Info("kept")
+	})
+	assert.Contains(t, buffer.String(), `"msg":"kept"`)
+}
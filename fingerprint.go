@@ -0,0 +1,74 @@
+package sloglambda
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"runtime"
+)
+
+var kErrorFingerprint = "errorFingerprint"
+
+var (
+	fingerprintUUID   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	fingerprintHex    = regexp.MustCompile(`(?i)\b(0x[0-9a-f]+|[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*|[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*)\b`)
+	fingerprintNumber = regexp.MustCompile(`[0-9]+`)
+)
+
+// WithErrorFingerprint configures the Handler to add an "errorFingerprint" field to records at
+// ERROR and above (see ErrorFingerprintEnricher).
+func WithErrorFingerprint() Option {
+	return enrichment(slog.LevelError, map[string]FieldType{kErrorFingerprint: FieldString}, ErrorFingerprintEnricher())
+}
+
+// ErrorFingerprintEnricher returns an Enricher that adds an "errorFingerprint" field identifying
+// the kind of error a record reports, for grouping and deduplicating errors in metric filters and
+// downstream tools.
+//
+// The fingerprint is a hash of the type of the first error attribute of the record, the message
+// of the error (or of the record, when it has no error attribute) with identifiers and numbers
+// removed, and the function that logged the record. Records for the same failure at the same call
+// site share a fingerprint even when the details in their messages differ.
+func ErrorFingerprintEnricher() Enricher {
+	return func(_ context.Context, record slog.Record) []slog.Attr {
+		return []slog.Attr{slog.String(kErrorFingerprint, errorFingerprint(record))}
+	}
+}
+
+func errorFingerprint(record slog.Record) string {
+	var err error
+	record.Attrs(func(a slog.Attr) bool {
+		err, _ = a.Value.Resolve().Any().(error)
+		return err == nil
+	})
+
+	message, errorType := record.Message, ""
+	if err != nil {
+		message = err.Error()
+		for inner := err; inner != nil; inner = errors.Unwrap(inner) {
+			errorType = fmt.Sprintf("%T", inner)
+		}
+	}
+
+	var function string
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		function = frame.Function
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s", errorType, normalizeErrorMessage(message), function)
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// normalizeErrorMessage replaces the parts of an error message that vary between occurrences of
+// the same error, such as IDs, addresses, and counts, with placeholders.
+func normalizeErrorMessage(message string) string {
+	message = fingerprintUUID.ReplaceAllString(message, "<uuid>")
+	message = fingerprintHex.ReplaceAllString(message, "<hex>")
+	return fingerprintNumber.ReplaceAllString(message, "<n>")
+}
@@ -0,0 +1,45 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithErrorFingerprint(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithErrorFingerprint()))
+
+	logFailure := func(err error) {
+		logger.Error("request failed", "error", err)
+	}
+
+	logFailure(fmt.Errorf("order 1234 (id 0b9e6c1a-77d2-4e38-9a35-0b7a2a8c8f10): %w", fs.ErrNotExist))
+	logFailure(fmt.Errorf("order 98 (id 5f0c1d2e-0000-4abc-8def-123456789abc): %w", fs.ErrNotExist))
+	logFailure(errors.New("order 1234: timeout"))
+	logger.Error("no error attribute")
+	logger.Info("not an error")
+
+	var fingerprints []any
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		fingerprints = append(fingerprints, record["errorFingerprint"])
+	}
+
+	require.Len(t, fingerprints, 5)
+	assert.Regexp(t, `^[0-9a-f]{16}$`, fingerprints[0])
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+	assert.NotNil(t, fingerprints[3])
+	assert.Nil(t, fingerprints[4])
+}
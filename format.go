@@ -0,0 +1,226 @@
+package sloglambda
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// valueFormat holds the Handler options that control how attribute values are normalized before
+// they are encoded. A nil *valueFormat uses the defaults.
+type valueFormat struct {
+	location *time.Location
+	duration DurationFormat
+
+	roundFloats    bool
+	floatPrecision int
+	largeInts      bool
+	nilPolicy      NilPolicy
+}
+
+// NilPolicy controls how attribute values that are nil, including interfaces holding a nil pointer,
+// are encoded.
+type NilPolicy int
+
+const (
+	// NilNull encodes nil values as null in both the JSON and text formats.
+	NilNull NilPolicy = iota
+	// NilString encodes nil values as the string "nil".
+	NilString
+	// NilOmit omits attributes with nil values from the record.
+	NilOmit
+)
+
+// WithNilPolicy configures how the Handler encodes nil attribute values. The default is NilNull.
+func WithNilPolicy(policy NilPolicy) Option {
+	return func(h *Handler) {
+		h.format.nilPolicy = policy
+	}
+}
+
+// omit reports whether the attribute value should be left out of the record.
+func (f *valueFormat) omit(v slog.Value) bool {
+	return f != nil && f.nilPolicy == NilOmit && v.Kind() == slog.KindAny && isNil(v.Any())
+}
+
+// maxSafeInteger is the largest integer a float64, and so a JavaScript number, represents exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// DurationFormat controls how time.Duration values are encoded.
+type DurationFormat int
+
+const (
+	// DurationString encodes durations using time.Duration.String, e.g. "1.5s".
+	DurationString DurationFormat = iota
+	// DurationSeconds encodes durations as a floating point number of seconds, e.g. 1.5.
+	DurationSeconds
+	// DurationMillis encodes durations as an integer number of milliseconds, e.g. 1500.
+	DurationMillis
+	// DurationNanos encodes durations as an integer number of nanoseconds, e.g. 1500000000.
+	DurationNanos
+)
+
+// WithDurationFormat configures how the Handler encodes time.Duration values. Numeric formats make
+// durations usable in aggregations such as percentiles in CloudWatch Logs Insights.
+func WithDurationFormat(format DurationFormat) Option {
+	return func(h *Handler) {
+		h.format.duration = format
+	}
+}
+
+// WithUTC configures the Handler to convert time values, including the record time, to UTC before
+// formatting them.
+func WithUTC() Option {
+	return WithLocation(time.UTC)
+}
+
+// WithLocation configures the Handler to convert time values, including the record time, to the
+// given location before formatting them. By default times are formatted in their own location,
+// which for the record time is the process's local time zone (see the TZ environment variable).
+func WithLocation(loc *time.Location) Option {
+	return func(h *Handler) {
+		if loc == nil {
+			h.invalidOption("WithLocation: nil location")
+		}
+		h.format.location = loc
+	}
+}
+
+// WithFloatPrecision configures the Handler to round floating point values to the given number of
+// digits after the decimal point.
+func WithFloatPrecision(digits int) Option {
+	return func(h *Handler) {
+		if digits < 0 {
+			h.invalidOption("WithFloatPrecision: negative precision %d", digits)
+		}
+		h.format.roundFloats = true
+		h.format.floatPrecision = digits
+	}
+}
+
+// WithLargeIntsAsStrings configures the Handler to encode integers outside the range a float64 can
+// represent exactly (±2^53-1) as strings, so JavaScript based log viewers do not lose precision.
+func WithLargeIntsAsStrings() Option {
+	return func(h *Handler) {
+		h.format.largeInts = true
+	}
+}
+
+func (f *valueFormat) normalize(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindTime:
+		return f.time(v.Time()).Format(time.RFC3339Nano)
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return f.durationValue(v.Duration())
+	case slog.KindFloat64:
+		return f.floatValue(v.Float64())
+	case slog.KindInt64:
+		return f.intValue(v.Int64())
+	case slog.KindString:
+		return v.String()
+	case slog.KindUint64:
+		return f.uintValue(v.Uint64())
+	case slog.KindLogValuer, slog.KindAny:
+		return f.normalizeAny(v.Any())
+	default:
+		panic(fmt.Sprintf("bad kind: %s", v.Kind()))
+	}
+}
+
+func (f *valueFormat) normalizeAny(val any) any {
+	if isNil(val) {
+		if f != nil && f.nilPolicy == NilString {
+			return "nil"
+		}
+		return nil
+	}
+
+	switch v := val.(type) {
+	case RawJSON:
+		return v.normalize()
+	case error:
+		return safeString(v.Error)
+	case json.Marshaler:
+		b, err := safeMarshalJSON(v)
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	default:
+		return val
+	}
+}
+
+// safeString calls fn, returning a placeholder describing the panic if it panics.
+func safeString(fn func() string) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("!PANIC: %v", r)
+		}
+	}()
+	return fn()
+}
+
+func safeMarshalJSON(m json.Marshaler) (b []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("!PANIC: %v", r)
+		}
+	}()
+	return m.MarshalJSON()
+}
+
+func (f *valueFormat) time(t time.Time) time.Time {
+	if f != nil && f.location != nil {
+		return t.In(f.location)
+	}
+	return t
+}
+
+func (f *valueFormat) durationValue(d time.Duration) any {
+	if f == nil {
+		return d.String()
+	}
+
+	switch f.duration {
+	case DurationSeconds:
+		return d.Seconds()
+	case DurationMillis:
+		return d.Milliseconds()
+	case DurationNanos:
+		return d.Nanoseconds()
+	default:
+		return d.String()
+	}
+}
+
+func (f *valueFormat) floatValue(v float64) any {
+	if f == nil || !f.roundFloats || math.IsInf(v, 0) || math.IsNaN(v) {
+		return v
+	}
+
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(v, 'f', f.floatPrecision, 64), 64)
+	if err != nil {
+		return v
+	}
+	return rounded
+}
+
+func (f *valueFormat) intValue(v int64) any {
+	if f != nil && f.largeInts && (v > maxSafeInteger || v < -maxSafeInteger) {
+		return strconv.FormatInt(v, 10)
+	}
+	return v
+}
+
+func (f *valueFormat) uintValue(v uint64) any {
+	if f != nil && f.largeInts && v > maxSafeInteger {
+		return strconv.FormatUint(v, 10)
+	}
+	return v
+}
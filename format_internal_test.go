@@ -0,0 +1,105 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicValuer struct{}
+
+func (panicValuer) LogValue() slog.Value {
+	panic("log value")
+}
+
+type loopValuer struct{}
+
+func (l loopValuer) LogValue() slog.Value {
+	return slog.AnyValue(l)
+}
+
+type panicError struct{}
+
+func (panicError) Error() string {
+	panic("error")
+}
+
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) {
+	panic("marshal")
+}
+
+type panicStringer struct{}
+
+func (panicStringer) String() string {
+	panic("string")
+}
+
+func Test_valueSafety(t *testing.T) {
+	t.Run("when a LogValuer panics", func(t *testing.T) {
+		r := logRecord{}
+		r.append(slog.Any("value", panicValuer{}))
+
+		assert.Contains(t, r["value"], "LogValue panicked")
+	})
+
+	t.Run("when a LogValuer never resolves", func(t *testing.T) {
+		r := logRecord{}
+		r.append(slog.Any("value", loopValuer{}))
+
+		assert.Contains(t, r["value"], "LogValue called too many times")
+	})
+
+	t.Run("when a LogValuer resolves to a group of LogValuers", func(t *testing.T) {
+		r := logRecord{}
+		r.append(slog.Group("group", slog.Any("value", panicValuer{})))
+
+		require.IsType(t, logRecord{}, r["group"])
+		assert.Contains(t, r["group"].(logRecord)["value"], "LogValue panicked")
+	})
+
+	t.Run("when an error panics", func(t *testing.T) {
+		r := logRecord{}
+		r.append(slog.Any("value", panicError{}))
+
+		assert.Equal(t, "!PANIC: error", r["value"])
+	})
+
+	t.Run("when a json.Marshaler panics", func(t *testing.T) {
+		r := logRecord{}
+		r.append(slog.Any("value", panicMarshaler{}))
+
+		assert.Equal(t, "!PANIC: marshal", r["value"])
+	})
+}
+
+func TestHandler_encode(t *testing.T) {
+	t.Run("when a nested value panics while encoding JSON", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(NewHandler(buffer, WithJSON()))
+
+		assert.NotPanics(t, func() {
+			logger.Info(t.Name(), "value", []any{panicMarshaler{}})
+		})
+
+		var result map[string]any
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &result))
+		assert.Contains(t, result["msg"], "failed to encode log record")
+	})
+
+	t.Run("when a fmt.Stringer panics while encoding text", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(NewHandler(buffer, WithText()))
+
+		assert.NotPanics(t, func() {
+			logger.Info(t.Name(), "value", panicStringer{})
+		})
+
+		assert.Contains(t, buffer.String(), `value="!PANIC: string"`)
+	})
+}
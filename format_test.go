@@ -0,0 +1,125 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUTC(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithUTC()))
+
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	logger.Info(t.Name(), "at", at)
+
+	assert.Contains(t, buffer.String(), `"at":"2024-06-01T17:00:00Z"`)
+	assert.Regexp(t, `"time":"[^"]+Z"`, buffer.String())
+}
+
+func TestWithLocation(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithLocation(time.FixedZone("CET", 60*60))))
+
+	logger.Info(t.Name(), "at", time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Contains(t, buffer.String(), `at="2024-06-01T13:00:00+01:00"`)
+}
+
+func TestWithDurationFormat(t *testing.T) {
+	cases := map[sloglambda.DurationFormat]string{
+		sloglambda.DurationString:  `"latency":"1.5s"`,
+		sloglambda.DurationSeconds: `"latency":1.5`,
+		sloglambda.DurationMillis:  `"latency":1500`,
+		sloglambda.DurationNanos:   `"latency":1500000000`,
+	}
+
+	for format, expected := range cases {
+		t.Run(expected, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithDurationFormat(format)))
+
+			logger.Info(t.Name(), "latency", 1500*time.Millisecond)
+
+			assert.Contains(t, buffer.String(), expected)
+		})
+	}
+}
+
+func TestWithFloatPrecision(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithFloatPrecision(2)))
+
+	logger.Info(t.Name(), "ratio", 2.0/3.0, "whole", 4.0)
+
+	assert.Contains(t, buffer.String(), `"ratio":0.67`)
+	assert.Contains(t, buffer.String(), `"whole":4`)
+}
+
+func TestWithLargeIntsAsStrings(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLargeIntsAsStrings()))
+
+	logger.Info(t.Name(),
+		"small", int64(1<<53-1),
+		"large", int64(1<<53),
+		"negative", int64(-1<<53),
+		"unsigned", uint64(1<<63),
+	)
+
+	assert.Contains(t, buffer.String(), `"small":9007199254740991`)
+	assert.Contains(t, buffer.String(), `"large":"9007199254740992"`)
+	assert.Contains(t, buffer.String(), `"negative":"-9007199254740992"`)
+	assert.Contains(t, buffer.String(), `"unsigned":"9223372036854775808"`)
+}
+
+func TestWithNilPolicy(t *testing.T) {
+	var nilPointer *time.Time
+	var nilError error
+
+	cases := []struct {
+		policy sloglambda.NilPolicy
+		json   []string
+		text   []string
+	}{
+		{sloglambda.NilNull, []string{`"ptr":null`, `"err":null`}, []string{`ptr=null`, `err=null`}},
+		{sloglambda.NilString, []string{`"ptr":"nil"`, `"err":"nil"`}, []string{`ptr="nil"`, `err="nil"`}},
+		{sloglambda.NilOmit, nil, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run("JSON", func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithNilPolicy(tc.policy)))
+
+			logger.Info(t.Name(), "ptr", nilPointer, "err", nilError)
+
+			for _, expected := range tc.json {
+				assert.Contains(t, buffer.String(), expected)
+			}
+			if tc.json == nil {
+				assert.NotContains(t, buffer.String(), `"ptr"`)
+				assert.NotContains(t, buffer.String(), `"err"`)
+			}
+		})
+
+		t.Run("Text", func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithNilPolicy(tc.policy)))
+
+			logger.Info(t.Name(), "ptr", nilPointer, "err", nilError)
+
+			for _, expected := range tc.text {
+				assert.Contains(t, buffer.String(), expected)
+			}
+			if tc.text == nil {
+				assert.NotContains(t, buffer.String(), `ptr=`)
+				assert.NotContains(t, buffer.String(), `err=`)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultForwardTimeout is the default time a ForwardWriter waits to connect or send records.
+const DefaultForwardTimeout = time.Second
+
+// ForwardWriter is an io.Writer that sends the records written by a Handler to a Fluentd or Fluent
+// Bit forward input, such as a FireLens log router sidecar, using the Forward protocol (MessagePack
+// over a TCP or Unix socket) instead of relying on stdout being scraped.
+//
+// The Handler must write JSON (see WithJSON). Each record is sent as a Forward message tagged with
+// the writer's tag, with the event time taken from the record's time field. The connection is
+// established on the first write and re-established once if sending fails.
+type ForwardWriter struct {
+	network string
+	address string
+	tag     string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewForwardWriter creates a ForwardWriter that sends records tagged with tag to the forward input
+// listening at address on the named network, "tcp" or "unix".
+func NewForwardWriter(network, address, tag string) *ForwardWriter {
+	return &ForwardWriter{
+		network: network,
+		address: address,
+		tag:     tag,
+		timeout: DefaultForwardTimeout,
+	}
+}
+
+// WithTimeout configures how long the ForwardWriter waits to connect or send records.
+func (f *ForwardWriter) WithTimeout(timeout time.Duration) *ForwardWriter {
+	f.timeout = timeout
+	return f
+}
+
+// Write implements io.Writer. p holds one or more newline terminated JSON records.
+//
+// When sending fails part way through, the records written completely before the failure are not
+// sent again over the new connection.
+func (f *ForwardWriter) Write(p []byte) (int, error) {
+	var messages []byte
+	var ends []int
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var err error
+		if messages, err = appendForwardMessage(messages, f.tag, line); err != nil {
+			return 0, err
+		}
+		ends = append(ends, len(messages))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.send(messages)
+	if err != nil {
+		f.disconnect()
+		if _, err := f.send(messages[sentMessages(ends, n):]); err != nil {
+			f.disconnect()
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// sentMessages returns the length of the messages that were written completely when n bytes of
+// the messages ending at ends were written.
+func sentMessages(ends []int, n int) int {
+	sent := 0
+	for _, end := range ends {
+		if end > n {
+			break
+		}
+		sent = end
+	}
+	return sent
+}
+
+func (f *ForwardWriter) send(messages []byte) (int, error) {
+	if f.conn == nil {
+		conn, err := net.DialTimeout(f.network, f.address, f.timeout)
+		if err != nil {
+			return 0, err
+		}
+		f.conn = conn
+	}
+
+	if err := f.conn.SetWriteDeadline(time.Now().Add(f.timeout)); err != nil {
+		return 0, err
+	}
+	return f.conn.Write(messages)
+}
+
+func (f *ForwardWriter) disconnect() {
+	if f.conn != nil {
+		_ = f.conn.Close()
+		f.conn = nil
+	}
+}
+
+// Close closes the connection to the forward input.
+func (f *ForwardWriter) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.conn == nil {
+		return nil
+	}
+	err := f.conn.Close()
+	f.conn = nil
+	return err
+}
+
+var _ io.WriteCloser = (*ForwardWriter)(nil)
+
+// appendForwardMessage appends a Forward protocol message, [tag, time, record], for the JSON
+// record to b.
+func appendForwardMessage(b []byte, tag string, record []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("forward: records must be JSON: %w", err)
+	}
+
+	b = append(b, 0x93)
+	b = appendMsgpackString(b, tag)
+	b = appendMsgpackEventTime(b, recordTime(fields))
+	return appendMsgpack(b, fields)
+}
@@ -0,0 +1,69 @@
+package sloglambda
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialConn accepts the first limit bytes written to it, then fails.
+type partialConn struct {
+	net.Conn
+	limit   int
+	written bytes.Buffer
+}
+
+func (c *partialConn) Write(p []byte) (int, error) {
+	n := min(len(p), c.limit)
+	c.written.Write(p[:n])
+	return n, errors.New("connection reset")
+}
+
+func (c *partialConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *partialConn) Close() error { return nil }
+
+func TestForwardWriter_partialWrite(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	records := []string{
+		`{"msg":"first","time":"2024-01-02T03:04:05Z"}`,
+		`{"msg":"second","time":"2024-01-02T03:04:06Z"}`,
+	}
+	first, err := appendForwardMessage(nil, "app", []byte(records[0]))
+	require.NoError(t, err)
+	second, err := appendForwardMessage(nil, "app", []byte(records[1]))
+	require.NoError(t, err)
+
+	partial := &partialConn{limit: len(first) + 3}
+	w := NewForwardWriter("tcp", listener.Addr().String(), "app")
+	w.conn = partial
+
+	_, err = w.Write([]byte(records[0] + "\n" + records[1] + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, second, <-received, "only the record that was not written completely is resent")
+	assert.Equal(t, append(first, second[:3]...), partial.written.Bytes())
+}
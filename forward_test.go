@@ -0,0 +1,57 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardWriter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	w := sloglambda.NewForwardWriter("tcp", listener.Addr().String(), "app")
+	logger := slog.New(sloglambda.NewHandler(w, sloglambda.WithJSON()))
+
+	when := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	record := slog.NewRecord(when, slog.LevelInfo, "hi", 0)
+	require.NoError(t, logger.Handler().Handle(context.Background(), record))
+	require.NoError(t, w.Close())
+
+	data := <-received
+
+	header := []byte{0x93, 0xa3, 'a', 'p', 'p', 0xd7, 0x00}
+	require.True(t, bytes.HasPrefix(data, header), "% x", data)
+
+	eventTime := data[len(header) : len(header)+8]
+	assert.Equal(t, uint32(when.Unix()), binary.BigEndian.Uint32(eventTime[:4]))
+	assert.Equal(t, uint32(6), binary.BigEndian.Uint32(eventTime[4:]))
+
+	body := data[len(header)+8:]
+	assert.Equal(t, byte(0x85), body[0], "map with level, msg, record, time, and type")
+	assert.Contains(t, string(body), "\xa3msg\xa2hi")
+	assert.Contains(t, string(body), "\xacfunctionName\xadtest-function")
+}
@@ -0,0 +1,43 @@
+package sloglambda
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+var (
+	kGoroutineCount = "goroutines"
+	kGoroutineID    = "goroutineId"
+)
+
+// WithGoroutineCount configures the Handler to include the number of goroutines that currently
+// exist. A count that keeps growing across warm invocations usually indicates a goroutine leak.
+func WithGoroutineCount() Option {
+	return func(h *Handler) {
+		h.goroutineCount = true
+	}
+}
+
+// WithGoroutineID configures the Handler to include the ID of the goroutine that logged the record.
+//
+// Reading the goroutine ID requires formatting the goroutine's stack, so this option is intended for
+// debugging only.
+func WithGoroutineID() Option {
+	return func(h *Handler) {
+		h.goroutineID = true
+	}
+}
+
+// currentGoroutineID parses the ID of the calling goroutine from the header of its stack trace,
+// which has the form "goroutine 123 [running]:".
+func currentGoroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,28 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithGoroutineCount(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithGoroutineCount()))
+
+	logger.Info(t.Name())
+
+	assert.Regexp(t, `"goroutines":\d+`, buffer.String())
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithGoroutineID()))
+
+	logger.Info(t.Name())
+
+	assert.Regexp(t, `"goroutineId":[1-9]\d*`, buffer.String())
+}
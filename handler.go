@@ -4,25 +4,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/aws/aws-lambda-go/lambdacontext"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
-	lambdaEnvLogLevel        = "AWS_LAMBDA_LOG_LEVEL"
-	lambdaEnvLogFormat       = "AWS_LAMBDA_LOG_FORMAT"
-	lambdaEnvFunctionName    = "AWS_LAMBDA_FUNCTION_NAME"
-	lambdaEnvFunctionVersion = "AWS_LAMBDA_FUNCTION_VERSION"
+	lambdaEnvLogLevel           = "AWS_LAMBDA_LOG_LEVEL"
+	lambdaEnvLogFormat          = "AWS_LAMBDA_LOG_FORMAT"
+	lambdaEnvFunctionName       = "AWS_LAMBDA_FUNCTION_NAME"
+	lambdaEnvFunctionVersion    = "AWS_LAMBDA_FUNCTION_VERSION"
+	lambdaEnvInitializationType = "AWS_LAMBDA_INITIALIZATION_TYPE"
 
 	traceLevelDebugOffset = 4
 	fatalLevelErrorOffset = 4
@@ -33,18 +34,73 @@ var (
 	kLambdaFunctionName    = "functionName"
 	kLambdaFunctionVersion = "version"
 	kLambdaRequestId       = "requestId"
+	kLambdaAlias           = "alias"
 	kLambdaLogType         = "type"
 )
 
 type Handler struct {
-	out         io.Writer
-	logType     string
-	mu          *sync.Mutex
-	level       slog.Leveler
-	json        bool
-	source      bool
-	excludeTime bool
-	gattr       []groupOrAttrs
+	sink             Sink
+	outputs          []*output
+	concurrentWriter bool
+	maxLineSize      int
+	buffers          *bufferPool
+	async            *asyncWriter
+	asyncQueueSize   int
+	asyncPolicy      BackpressurePolicy
+	asyncTimeout     time.Duration
+	logType          string
+	mu               *sync.Mutex
+	level            slog.Leveler
+	json             bool
+	levelValue       bool
+	textHeader       []string
+	terminator       string
+	encoder          Encoder
+	source           bool
+	sourceFormat     SourceFormat
+	excludeTime      bool
+	phase            bool
+	memoryStats      bool
+	goroutineCount   bool
+	goroutineID      bool
+	sequence         bool
+	traceContext     bool
+	insights         bool
+	elapsed          bool
+	contextError     bool
+	snapStart        bool
+	callerAccount    bool
+	buildInfo        slog.Attr
+	gattr            []groupOrAttrs
+
+	budget          *invocationBudget
+	adaptive        *AdaptiveLevel
+	messageSampling *messageSampling
+	deadlineGuard   time.Duration
+	overrides       *LevelOverrides
+	suppress        []suppressRule
+	groupCollisions CollisionPolicy
+	reservedPolicy  CollisionPolicy
+
+	tenantExtractor func(context.Context) string
+	annotations     *annotations
+	enrichers       []levelEnricher
+	interceptors    []Interceptor
+	format          valueFormat
+	schema          *Schema
+	errorHandler    func(context.Context, error)
+	lastError       bool
+	lastErrorKeys   []string
+	alarm           *alarmMarker
+
+	invocations *invocationTracker
+	stats       *handlerStats
+	deadLetters *deadLetters
+
+	diagnostics  bool
+	levelSource  string
+	formatOption string
+	optionErrs   []error
 }
 
 type Option func(*Handler)
@@ -54,13 +110,19 @@ type Option func(*Handler)
 // The log level determines which log messages will be processed by the Handler.
 func WithLevel(level slog.Leveler) Option {
 	return func(h *Handler) {
+		if level == nil {
+			h.invalidOption("WithLevel: nil level")
+			return
+		}
 		h.level = level
+		h.levelSource = settingOption
 	}
 }
 
 // WithJSON configures the Handler to output log messages in JSON format.
 func WithJSON() Option {
 	return func(h *Handler) {
+		h.setFormatOption("WithJSON")
 		h.json = true
 	}
 }
@@ -68,10 +130,49 @@ func WithJSON() Option {
 // WithText configures the Handler to output log messages in text format.
 func WithText() Option {
 	return func(h *Handler) {
+		h.setFormatOption("WithText")
 		h.json = false
 	}
 }
 
+// WithTextHeader configures the Handler to start text records with the given top level fields, in
+// the order given, instead of sorting every field by key. The remaining fields follow, sorted by
+// key. Keys are the names the fields are written with, for example:
+//
+//	sloglambda.WithTextHeader("time", "level", "msg")
+//
+// JSON records are not affected.
+func WithTextHeader(keys ...string) Option {
+	return func(h *Handler) {
+		h.textHeader = slices.Clone(keys)
+	}
+}
+
+// WithTerminator configures the string written at the end of every record, instead of "\n", for
+// example "\r\n" for consumers that expect CRLF line endings. The terminator may not be empty or
+// contain a carriage return that is not followed by a line feed.
+//
+// Records never contain line breaks of their own: they are escaped in JSON, and quoted in text.
+func WithTerminator(terminator string) Option {
+	return func(h *Handler) {
+		if terminator == "" || strings.Contains(strings.ReplaceAll(terminator, "\r\n", ""), "\r") {
+			h.invalidOption("WithTerminator: invalid terminator %q", terminator)
+			return
+		}
+		h.terminator = terminator
+	}
+}
+
+// terminate replaces the newline at the end of the encoded record in buf with the Handler's
+// terminator. Records that do not end with a newline are left unchanged.
+func (h *Handler) terminate(buf *bytes.Buffer) {
+	if h.terminator == "" || h.terminator == "\n" || buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		return
+	}
+	buf.Truncate(buf.Len() - 1)
+	buf.WriteString(h.terminator)
+}
+
 // WithSource configures the Handler to include source code information in log messages.
 func WithSource() Option {
 	return func(h *Handler) {
@@ -86,6 +187,27 @@ func WithType(logType string) Option {
 	}
 }
 
+// WithWriter configures the Handler to write log messages to w.
+//
+// It is intended for use with Handler.WithOptions; NewHandler accepts the writer directly.
+func WithWriter(w io.Writer) Option {
+	return func(h *Handler) {
+		h.sink = WriterSink(w)
+		h.mu = new(sync.Mutex)
+	}
+}
+
+// WithConcurrentWriter configures the Handler to write records without holding its lock, for
+// writers that are safe for concurrent use such as os.Stdout, where a single write of fewer than
+// PIPE_BUF bytes is atomic.
+//
+// Records are always formatted before being written with a single call to Write.
+func WithConcurrentWriter() Option {
+	return func(h *Handler) {
+		h.concurrentWriter = true
+	}
+}
+
 // WithoutTime configures the Handler to exclude the time field from log messages.
 func WithoutTime() Option {
 	return func(h *Handler) {
@@ -100,42 +222,104 @@ func WithoutTime() Option {
 // - AWS_LAMBDA_LOG_FORMAT: The log format to use. Valid values are "json" and "text".
 //
 // See more here: https://docs.aws.amazon.com/lambda/latest/dg/monitoring-cloudwatchlogs-advanced.html
+//
+// The "type" field and source information can also be configured with SLOG_LAMBDA_TYPE and
+// SLOG_LAMBDA_SOURCE. Invalid values are ignored; use ConfigFromEnv to detect them. Use
+// WithLevelSources to read the level from other environment variables.
 func NewHandler(w io.Writer, options ...Option) *Handler {
+	config, _ := ConfigFromEnv()
+
 	h := &Handler{
-		out:     w,
+		sink:    WriterSink(w),
 		mu:      new(sync.Mutex),
-		level:   loggerLevelFromLambdaEnv(),
-		json:    loggerIsJSON(),
-		source:  false,
-		logType: "app.log",
+		json:    config.Format == FormatJSON,
+		source:  config.Source,
+		logType: config.Type,
+
+		maxLineSize: DefaultMaxLineSize,
+		buffers:     defaultBufferPool,
+
+		invocations: newInvocationTracker(),
+		stats:       new(handlerStats),
+		deadLetters: new(deadLetters),
 	}
 
+	WithLevelSources(defaultLevelSources...)(h)
+
 	for _, opt := range options {
 		opt(h)
 	}
 
+	h.start()
+
 	return h
 }
 
-func loggerLevelFromLambdaEnv() slog.Level {
-	return loggerLevelFromString(os.Getenv(lambdaEnvLogLevel))
+// start starts the asynchronous writer and writes the diagnostics record, when the options
+// applied to the Handler configure them.
+func (h *Handler) start() {
+	if h.asyncQueueSize > 0 {
+		h.async = newAsyncWriter(h)
+	}
+
+	if h.diagnostics && h.sink != Sink(writerSink{}) {
+		h.writeDiagnostics()
+	}
+}
+
+// ErrInvalidOption is wrapped by the errors NewHandlerE returns for invalid or conflicting options.
+var ErrInvalidOption = errors.New("invalid option")
+
+// NewHandlerE creates a new Handler like NewHandler, but returns an error if w is nil or any of the
+// options are invalid or conflict with each other, instead of silently accepting them.
+func NewHandlerE(w io.Writer, options ...Option) (*Handler, error) {
+	h := NewHandler(w, options...)
+	if w == nil {
+		h.invalidOption("nil writer")
+	}
+
+	if err := errors.Join(h.optionErrs...); err != nil {
+		return nil, err
+	}
+	return h, nil
 }
 
-func loggerLevelFromString(level string) slog.Level {
-	switch strings.ToLower(strings.TrimSpace(level)) {
-	case "trace":
-		return slog.LevelDebug - traceLevelDebugOffset
-	case "debug":
-		return slog.LevelDebug
-	case "warn":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	case "fatal":
-		return slog.LevelError + fatalLevelErrorOffset
-	default:
-		return slog.LevelInfo
+// invalidOption records an error for an Option, returned by NewHandlerE.
+func (h *Handler) invalidOption(format string, args ...any) {
+	h.optionErrs = append(h.optionErrs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...))
+}
+
+// setFormatOption records the Option that selected the output format, so conflicting format
+// options are reported.
+func (h *Handler) setFormatOption(name string) {
+	if h.formatOption != "" && h.formatOption != name {
+		h.invalidOption("%s conflicts with %s", name, h.formatOption)
 	}
+	h.formatOption = name
+}
+
+// WithInvocationBudget limits the number of records and bytes written for a single invocation.
+//
+// Once either limit is exceeded, further DEBUG and INFO records for the invocation are dropped.
+// WARN and above are always written. When the invocation ends (see Handler.EndInvocation) a single
+// summary record reporting the number of suppressed records is written.
+//
+// A limit of zero or less disables that limit.
+func WithInvocationBudget(maxRecords int, maxBytes int) Option {
+	return func(h *Handler) {
+		if maxRecords < 0 || maxBytes < 0 {
+			h.invalidOption("WithInvocationBudget: negative limit")
+		}
+		h.budget = &invocationBudget{
+			maxRecords: maxRecords,
+			maxBytes:   maxBytes,
+		}
+	}
+}
+
+func loggerLevelFromString(level string) slog.Level {
+	l, _ := ParseLevel(level)
+	return l
 }
 
 func lambdaLoggerLevelString(l slog.Level) string {
@@ -162,23 +346,71 @@ func lambdaLoggerLevelString(l slog.Level) string {
 	}
 }
 
-func loggerIsJSON() bool {
-	env := os.Getenv(lambdaEnvLogFormat)
-	return strings.ToLower(strings.TrimSpace(env)) == "json"
+var kLevelValue = "levelValue"
+
+// WithLevelValue configures the Handler to add a "levelValue" field with the numeric slog.Level to
+// records whose level is not one of DEBUG, INFO, WARN, or ERROR, such as "TRACE" or "ERROR+2", so
+// consumers can compare levels without parsing the level names.
+func WithLevelValue() Option {
+	return func(h *Handler) {
+		h.levelValue = true
+	}
+}
+
+func isStandardLevel(l slog.Level) bool {
+	switch l {
+	case slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError:
+		return true
+	default:
+		return false
+	}
 }
 
 func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
-	return level >= h.level.Level()
+	return h.enabled(level)
 }
 
 func (h *Handler) WithAttrs(attr []slog.Attr) slog.Handler {
+	internedKeys.intern(attr)
 	return h.copy(groupOrAttrs{attrs: attr})
 }
 
 func (h *Handler) WithGroup(name string) slog.Handler {
+	internedKeys.get(name, true)
 	return h.copy(groupOrAttrs{group: name})
 }
 
+// WithOptions returns a copy of the Handler with the given options applied on top of its current
+// configuration. Attributes and groups added with WithAttrs and WithGroup are preserved.
+//
+// The copy shares the Handler's invocation state and statistics. Options take effect as they do in
+// NewHandler: WithLevelSources replaces the level unless a level is also given with WithLevel,
+// WithDiagnostics writes the configuration of the copy, and WithAsync gives the copy its own queue,
+// so the copy must be closed (see Handler.Close).
+func (h *Handler) WithOptions(options ...Option) *Handler {
+	c := *h
+	c.formatOption = ""
+	c.optionErrs = nil
+	c.levelSource = ""
+	c.asyncQueueSize = 0
+	c.diagnostics = false
+
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	if c.levelSource == "" {
+		c.levelSource = h.levelSource
+	}
+	c.start()
+	if c.asyncQueueSize == 0 {
+		c.asyncQueueSize = h.asyncQueueSize
+	}
+	c.diagnostics = c.diagnostics || h.diagnostics
+
+	return &c
+}
+
 func (h *Handler) copy(g groupOrAttrs) *Handler {
 	c := *h
 	c.gattr = make([]groupOrAttrs, len(h.gattr)+1)
@@ -188,45 +420,131 @@ func (h *Handler) copy(g groupOrAttrs) *Handler {
 }
 
 func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	h.intercept(ctx, &record)
+
+	if !h.overridden(record) {
+		return nil
+	}
+
+	inv := h.invocations.get(requestIDFromContext(ctx))
+	if pattern, ok := h.suppressed(record.Message); ok {
+		inv.silence(pattern)
+		h.stats.dropped.Add(1)
+		return nil
+	}
+	if !h.admit(ctx, inv, record) {
+		h.stats.dropped.Add(1)
+		return nil
+	}
+
+	if h.annotations != nil {
+		h.annotations.mirror(ctx, &h.format, h.gattr, record)
+	}
+
+	if h.lastError && record.Level >= slog.LevelError {
+		h.captureError(ctx, record)
+	}
+
+	n, err := h.emit(ctx, record)
+	inv.record(record.Level, n)
+
+	return err
+}
+
+// emit formats the record and writes it to the output, returning the number of bytes written.
+func (h *Handler) emit(ctx context.Context, record slog.Record) (int, error) {
 	value := make(logRecord, 10)
 	topLevel := value
 
 	value.append(slog.String(slog.LevelKey, lambdaLoggerLevelString(record.Level)))
-	value.append(slog.String(slog.MessageKey, record.Message))
+	if h.levelValue && !isStandardLevel(record.Level) {
+		value.append(slog.Int(kLevelValue, int(record.Level)))
+	}
+	value.append(slog.String(h.messageKey(), h.message(record)))
 
 	if !record.Time.IsZero() && !h.excludeTime {
-		value.append(slog.Time(slog.TimeKey, record.Time))
+		value.appendWith(slog.Time(h.timeKey(), record.Time), &h.format)
+	}
+
+	if h.sequence {
+		value.append(slog.Uint64(kSequence, executionEnvironment.nextSequence()))
+	}
+
+	if h.contextError {
+		if ctxErr := contextError(ctx); ctxErr != "" {
+			value.append(slog.String(kContextError, ctxErr))
+		}
 	}
 
-	lambdaGroup := make(logRecord, 3)
-	if value, ok := os.LookupEnv(lambdaEnvFunctionName); ok {
-		lambdaGroup.append(slog.String(kLambdaFunctionName, value))
+	if h.elapsed {
+		if start, ok := h.invocationStart(ctx); ok {
+			value.append(slog.Int64(kElapsed, time.Since(start).Milliseconds()))
+		}
 	}
-	if value, ok := os.LookupEnv(lambdaEnvFunctionVersion); ok {
-		lambdaGroup.append(slog.String(kLambdaFunctionVersion, value))
+
+	lambdaGroup := make(logRecord, 6)
+	for _, attr := range lambdaRecordAttrs(ctx, false) {
+		if h.insights && attr.Key == kLambdaRequestId {
+			value.append(attr)
+			continue
+		}
+		lambdaGroup.append(attr)
 	}
 
-	if lc, _ := lambdacontext.FromContext(ctx); lc != nil {
-		lambdaGroup.append(slog.String(kLambdaRequestId, lc.AwsRequestID))
+	if h.phase {
+		lambdaGroup.append(slog.String(kLambdaPhase, executionEnvironment.phase()))
 	}
 
 	if len(lambdaGroup) > 0 {
 		value[kLambdaRecord] = lambdaGroup
 	}
 
+	if h.snapStart {
+		for _, attr := range snapStartState.attrs(requestIDFromContext(ctx), time.Now()) {
+			value.append(attr)
+		}
+	}
+
 	if h.logType != "" {
 		value[kLambdaLogType] = h.logType
 	}
 
-	if record.PC != 0 && h.source {
-		frames := runtime.CallersFrames([]uintptr{record.PC})
-		frame, _ := frames.Next()
+	if tenantID := h.tenantID(ctx); tenantID != "" {
+		value[kTenantID] = tenantID
+	}
+
+	if h.callerAccount {
+		if accountID, ok := CallerAccountFromContext(ctx); ok {
+			value[kCallerAccount] = accountID
+		}
+		if accountID := claimedCallerAccount(ctx); accountID != "" {
+			value[kClaimedCallerAccount] = accountID
+		}
+	}
+
+	if h.traceContext {
+		for _, attr := range traceContextAttrs(ctx) {
+			value.append(attr)
+		}
+	}
 
-		value.append(slog.Group(slog.SourceKey,
-			slog.String("function", frame.Function),
-			slog.String("file", frame.File),
-			slog.Int("line", frame.Line),
-		))
+	value.append(h.buildInfo)
+
+	if h.memoryStats {
+		value.append(memoryStats.attr())
+	}
+
+	if h.goroutineCount {
+		value.append(slog.Int(kGoroutineCount, runtime.NumGoroutine()))
+	}
+	if h.goroutineID {
+		value.append(slog.Uint64(kGoroutineID, currentGoroutineID()))
+	}
+
+	h.enrich(ctx, record, value)
+
+	if record.PC != 0 && h.source {
+		value.append(h.sourceAttr(record.PC))
 	}
 
 	gattr := h.gattr
@@ -236,66 +554,190 @@ func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 		}
 	}
 
+	reserved := h.reservedKeys(topLevel)
+
 	for _, ga := range gattr {
 		if ga.group == "" {
 			for _, a := range ga.attrs {
-				value.append(a)
+				if err := h.addUserAttr(ctx, topLevel, value, reserved, a); err != nil {
+					return 0, err
+				}
 			}
 		} else {
-			group := make(logRecord, 10)
-			value[ga.group] = group
+			group, err := h.openGroup(value, ga.group)
+			if err != nil {
+				h.reportError(ctx, err)
+				return 0, err
+			}
+			value = group
+			reserved = nil
+		}
+	}
+
+	if record.NumAttrs() > 0 {
+		for _, name := range scopesFromContext(ctx) {
+			group, err := h.openGroup(value, name)
+			if err != nil {
+				h.reportError(ctx, err)
+				return 0, err
+			}
 			value = group
+			reserved = nil
 		}
 	}
 
+	var reserveErr error
 	record.Attrs(func(a slog.Attr) bool {
-		value.append(a)
-		return true
+		reserveErr = h.addUserAttr(ctx, topLevel, value, reserved, a)
+		return reserveErr == nil
 	})
+	if reserveErr != nil {
+		return 0, reserveErr
+	}
 
 	topLevel.clean()
 
-	buf := getBuffer()
-	defer putBuffer(buf)
+	if h.schema != nil {
+		if err := h.schema.validate(topLevel); err != nil {
+			if h.schema.Panic {
+				panic(err)
+			}
+			h.reportError(ctx, err)
+		}
+	}
+
+	h.writeOutputs(ctx, record.Level, topLevel)
 
-	if h.json {
-		if err := json.NewEncoder(buf).Encode(topLevel); err != nil {
-			h.mu.Lock()
-			defer h.mu.Unlock()
+	buf := h.buffers.get()
+	defer h.buffers.put(buf)
 
-			fmt.Fprintf(h.out, `{"level":"ERROR","msg":"failed to encode log record: %v"}`, err)
-			fmt.Fprintln(h.out)
-			return err
+	if err := h.encode(buf, topLevel); err != nil {
+		h.stats.encodeErrors.Add(1)
+		h.reportError(ctx, err)
+
+		if fallbackErr := h.deadLetter(buf, record, topLevel, err); fallbackErr == nil {
+			_, _ = h.write(ctx, buf.Bytes(), record.Level, false)
 		}
-	} else {
-		if err := writeTextRecord(buf, topLevel, ""); err != nil {
-			h.mu.Lock()
-			defer h.mu.Unlock()
+		return 0, err
+	}
 
-			fmt.Fprintf(h.out, `level=ERROR msg="failed to encode log record: %v"`, err)
-			fmt.Fprintln(h.out)
-			return err
+	if h.maxLineSize > 0 && buf.Len() > h.maxLineSize {
+		size := buf.Len()
+		if err := h.truncate(buf, topLevel); err != nil {
+			h.reportError(ctx, err)
+			return 0, err
 		}
-		// Remove the last trailing space
-		buf.Truncate(buf.Len() - 1)
-		buf.Write([]byte("\n"))
+		h.reportError(ctx, fmt.Errorf("%w: truncated from %d to %d bytes", ErrRecordTooLarge, size, buf.Len()))
 	}
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	n, err := h.write(ctx, buf.Bytes(), record.Level, true)
+	if err != nil && h.async == nil {
+		h.reportError(ctx, err)
+	}
 
-	_, err := io.Copy(h.out, buf)
-	return err
+	return n, err
+}
+
+// write writes an encoded record to the output, or queues it if the Handler is asynchronous. When
+// count is true the record is counted at level in the Handler's statistics once it is written.
+func (h *Handler) write(ctx context.Context, p []byte, level slog.Level, count bool) (int, error) {
+	if h.async != nil {
+		return h.async.enqueue(ctx, h, p, level, count)
+	}
+	return h.writeOut(ctx, p, level, count)
+}
+
+// writeOut writes an encoded record to the sink with a single call to Write, holding the Handler's
+// lock unless the sink is safe for concurrent use.
+func (h *Handler) writeOut(ctx context.Context, p []byte, level slog.Level, count bool) (int, error) {
+	if !h.concurrentWriter {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+	}
+	if err := h.sink.Write(ctx, p); err != nil {
+		return 0, err
+	}
+	if count {
+		h.stats.written(level, len(p))
+	}
+	return len(p), nil
+}
+
+// addUserAttr resolves collisions of an attribute given to the logger with the fields written by
+// the Handler and adds it to value, expanding the attributes wrapped by Verbose.
+func (h *Handler) addUserAttr(ctx context.Context, topLevel, value logRecord, reserved map[string]struct{}, a slog.Attr) error {
+	if v, ok := a.Value.Any().(*verboseAttrs); ok {
+		if h.level.Level() > slog.LevelDebug {
+			return nil
+		}
+		for _, a := range v.attrs {
+			if err := h.addUserAttr(ctx, topLevel, value, reserved, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	a, err := h.reserveAttr(ctx, value, reserved, a)
+	if err != nil {
+		return err
+	}
+	h.appendUserAttr(topLevel, value, a)
+	return nil
+}
+
+// appendUserAttr adds an attribute given to the logger to value, the record or group it belongs in.
+// Attributes with special meaning to the Handler are applied to the top level record instead.
+func (h *Handler) appendUserAttr(topLevel, value logRecord, a slog.Attr) {
+	if logType, ok := a.Value.Any().(logTypeOverride); ok {
+		topLevel[kLambdaLogType] = string(logType)
+		return
+	}
+
+	value.appendWith(a, &h.format)
+}
+
+// encode writes the record to buf with the Handler's encoder (see WithEncoder), followed by the
+// Handler's terminator.
+func (h *Handler) encode(buf *bytes.Buffer, record logRecord) error {
+	return h.encodeWith(h.recordEncoder(), buf, record)
+}
+
+// encodeWith writes the record to buf with enc, followed by the Handler's terminator.
+//
+// A panic raised while encoding, for example by a value's MarshalJSON or String method, is returned
+// as an error instead of taking down the function.
+func (h *Handler) encodeWith(enc Encoder, buf *bytes.Buffer, record logRecord) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf.Reset()
+			err = fmt.Errorf("panic while encoding: %v", r)
+		}
+	}()
+
+	if err := enc.Encode(buf, record); err != nil {
+		return err
+	}
+	if _, ok := enc.(BinaryEncoder); !ok {
+		h.terminate(buf)
+	}
+	return nil
 }
 
 var _ slog.Handler = (*Handler)(nil)
 
-type logRecord map[string]any
+// logRecord is the record being built by a Handler.
+type logRecord = Fields
 
 func (r logRecord) append(attr slog.Attr) {
+	r.appendWith(attr, nil)
+}
+
+// appendWith adds the attribute to the record, normalizing its value with the given format.
+func (r logRecord) appendWith(attr slog.Attr, f *valueFormat) {
 	attr.Value = attr.Value.Resolve()
 
-	if attr.Equal(slog.Attr{}) {
+	if attr.Equal(slog.Attr{}) || f.omit(attr.Value) {
 		return
 	}
 
@@ -307,16 +749,20 @@ func (r logRecord) append(attr slog.Attr) {
 
 		if attr.Key == "" {
 			for _, a := range group {
-				r.append(a)
+				r.appendWith(a, f)
 			}
 		} else {
-			r[attr.Key] = make(logRecord, len(group))
+			sub, ok := r[attr.Key].(logRecord)
+			if !ok {
+				sub = make(logRecord, len(group))
+				r[attr.Key] = sub
+			}
 			for _, a := range group {
-				r[attr.Key].(logRecord).append(a)
+				sub.appendWith(a, f)
 			}
 		}
 	} else {
-		r[attr.Key] = normalizeValue(attr.Value)
+		r[attr.Key] = f.normalize(attr.Value)
 	}
 }
 
@@ -340,33 +786,18 @@ func (r logRecord) keys() []string {
 	return keys
 }
 
-var bufferPool = sync.Pool{
-	New: func() any {
-		b := bytes.NewBuffer(nil)
-		b.Grow(1024)
-		return b
-	},
-}
-
-func getBuffer() *bytes.Buffer {
-	return bufferPool.Get().(*bytes.Buffer)
-}
-
-func putBuffer(b *bytes.Buffer) {
-	const maxBufferSize = 16 << 10
-
-	if b.Cap() <= maxBufferSize {
-		b.Reset()
-		bufferPool.Put(b)
-	}
-}
-
 type groupOrAttrs struct {
 	group string      // group name if non-empty
 	attrs []slog.Attr // attrs if non-empty
 }
 
 func writeTextRecord(w io.Writer, record logRecord, path string) error {
+	return writeTextRecordWithHeader(w, record, path, nil)
+}
+
+// writeTextRecordWithHeader writes the fields of record named in header first, in the order given,
+// followed by the remaining fields sorted by key.
+func writeTextRecordWithHeader(w io.Writer, record logRecord, path string, header []string) error {
 	if record == nil {
 		return nil
 	}
@@ -374,8 +805,24 @@ func writeTextRecord(w io.Writer, record logRecord, path string) error {
 	keys := record.keys()
 	slices.Sort(keys)
 
+	if len(header) > 0 {
+		ordered := make([]string, 0, len(keys))
+		for _, key := range header {
+			if _, ok := record[key]; ok && !slices.Contains(ordered, key) {
+				ordered = append(ordered, key)
+			}
+		}
+		for _, key := range keys {
+			if !slices.Contains(header, key) {
+				ordered = append(ordered, key)
+			}
+		}
+		keys = ordered
+	}
+
 	for _, key := range keys {
 		value := record[key]
+		key = textKey(key)
 		if path != "" {
 			key = path + "." + key
 		}
@@ -390,15 +837,19 @@ func writeTextRecord(w io.Writer, record logRecord, path string) error {
 			if err := writeTextRecord(w, v, key); err != nil {
 				return err
 			}
+		case nil:
+			w.Write([]byte("null"))
 		case string:
 			w.Write([]byte(strconv.Quote(v)))
+		case json.RawMessage:
+			w.Write([]byte(strconv.Quote(string(v))))
 		case fmt.Stringer:
 			// This is here because nilaway can't figure out that v is not nil
 			if v != nil {
-				w.Write([]byte(v.String()))
+				writeTextToken(w, safeString(v.String))
 			}
 		default:
-			fmt.Fprintf(w, "%v", v)
+			writeTextToken(w, fmt.Sprintf("%v", v))
 		}
 
 		if _, ok := value.(logRecord); !ok {
@@ -409,40 +860,38 @@ func writeTextRecord(w io.Writer, record logRecord, path string) error {
 	return nil
 }
 
-func normalizeValue(v slog.Value) any {
-	switch v.Kind() {
-	case slog.KindTime:
-		return v.Time().Format(time.RFC3339Nano)
-	case slog.KindBool:
-		return v.Bool()
-	case slog.KindDuration:
-		return v.Duration().String()
-	case slog.KindFloat64:
-		return v.Float64()
-	case slog.KindInt64:
-		return v.Int64()
-	case slog.KindString:
-		return v.String()
-	case slog.KindUint64:
-		return v.Uint64()
-	case slog.KindLogValuer, slog.KindAny:
-		return normalizeAnyValue(v.Any())
-	default:
-		panic(fmt.Sprintf("bad kind: %s", v.Kind()))
+// writeTextToken writes an unquoted value, quoting it instead if it could be mistaken for the
+// separators of the text format, so a text record never spans more than one line and can be parsed
+// back unambiguously.
+func writeTextToken(w io.Writer, s string) {
+	if needsTextQuoting(s, false) {
+		s = strconv.Quote(s)
+	}
+	w.Write([]byte(s))
+}
+
+// textKey returns a key of a text record, quoted if it is empty or contains a separator, including
+// the dot that joins the keys of groups.
+func textKey(key string) string {
+	if needsTextQuoting(key, true) {
+		return strconv.Quote(key)
 	}
+	return key
 }
 
-func normalizeAnyValue(val any) any {
-	switch v := val.(type) {
-	case error:
-		return v.Error()
-	case json.Marshaler:
-		b, err := v.MarshalJSON()
-		if err != nil {
-			return err.Error()
+// needsTextQuoting reports whether s is empty or contains a space, equals sign, quote, line break,
+// other unprintable character, or invalid UTF-8, or, for keys, a dot.
+func needsTextQuoting(s string, key bool) bool {
+	if s == "" {
+		return true
+	}
+	if !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r == ' ' || r == '=' || r == '"' || (key && r == '.') || !unicode.IsPrint(r) {
+			return true
 		}
-		return string(b)
-	default:
-		return val
 	}
+	return false
 }
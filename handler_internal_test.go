@@ -93,6 +93,14 @@ func Test_logRecord(t *testing.T) {
 
 			assert.Equal(t, logRecord{"foo": "bar"}, r)
 		})
+
+		t.Run("when given a group that already exists", func(t *testing.T) {
+			r := logRecord{}
+			r.append(slog.Group("foo", slog.String("bar", "baz")))
+			r.append(slog.Group("foo", slog.String("qux", "quux")))
+
+			assert.Equal(t, logRecord{"foo": logRecord{"bar": "baz", "qux": "quux"}}, r)
+		})
 	})
 }
 
@@ -144,6 +152,14 @@ func Test_writeTextRecord(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, `foo.bar.baz=1 `, buffer.String())
 	})
+
+	t.Run("when a key or unquoted value contains a line break", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		err := writeTextRecord(buffer, logRecord{"a\rb": []string{"x\ny"}}, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `"a\rb"="[x\ny]" `, buffer.String())
+	})
 }
 
 type stringerValue struct{}
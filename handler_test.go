@@ -8,8 +8,10 @@ import (
 	"log/slog"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"testing/slogtest"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 	sloglambda "github.com/maddiesch/slog-lambda"
@@ -191,3 +193,192 @@ func BenchmarkText(b *testing.B) {
 		logger.Info("test", "count", i)
 	}
 }
+
+func TestNewHandlerE(t *testing.T) {
+	t.Run("with valid options", func(t *testing.T) {
+		h, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug))
+		require.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("with invalid options", func(t *testing.T) {
+		h, err := sloglambda.NewHandlerE(nil,
+			sloglambda.WithJSON(),
+			sloglambda.WithText(),
+			sloglambda.WithInvocationBudget(-1, 0),
+			sloglambda.WithFloatPrecision(-2),
+			sloglambda.WithSchema(&sloglambda.Schema{Types: map[string]sloglambda.FieldType{"id": "uuid"}}),
+		)
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+		assert.ErrorContains(t, err, "nil writer")
+		assert.ErrorContains(t, err, "WithText conflicts with WithJSON")
+		assert.ErrorContains(t, err, "WithInvocationBudget: negative limit")
+		assert.ErrorContains(t, err, "WithFloatPrecision: negative precision -2")
+		assert.ErrorContains(t, err, `WithSchema: unknown type "uuid" for "id"`)
+	})
+}
+
+func TestHandler_WithOptions(t *testing.T) {
+	appBuffer := new(bytes.Buffer)
+	auditBuffer := new(bytes.Buffer)
+
+	base := sloglambda.NewHandler(appBuffer, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithLevel(slog.LevelWarn))
+	derived := base.WithAttrs([]slog.Attr{slog.String("service", "orders")}).WithGroup("request").(*sloglambda.Handler)
+
+	audit := derived.WithOptions(sloglambda.WithType("audit.log"), sloglambda.WithLevel(slog.LevelDebug), sloglambda.WithWriter(auditBuffer))
+
+	slog.New(audit).Info(t.Name(), "id", "r-1")
+	slog.New(derived).Info(t.Name(), "id", "r-1")
+
+	assert.Empty(t, appBuffer.String())
+	assert.JSONEq(t, `{"level":"INFO","msg":"TestHandler_WithOptions","record":{"functionName":"test-function","version":"$LATEST"},"request":{"id":"r-1"},"service":"orders","type":"audit.log"}`, auditBuffer.String())
+	assert.Equal(t, slog.LevelWarn, derived.Level())
+	assert.Equal(t, "app.log", derived.Type())
+}
+
+func TestHandler_WithOptionsSetup(t *testing.T) {
+	base := sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelWarn))
+
+	t.Run("level sources replace the level", func(t *testing.T) {
+		c := base.WithOptions(sloglambda.WithLevelSources(sloglambda.DefaultLevelSource(slog.LevelDebug)))
+		assert.Equal(t, slog.LevelDebug, c.Level())
+		assert.Equal(t, slog.LevelWarn, base.Level())
+	})
+
+	t.Run("level takes precedence over level sources", func(t *testing.T) {
+		c := base.WithOptions(sloglambda.WithLevel(slog.LevelError), sloglambda.WithLevelSources(sloglambda.DefaultLevelSource(slog.LevelDebug)))
+		assert.Equal(t, slog.LevelError, c.Level())
+	})
+
+	t.Run("async", func(t *testing.T) {
+		out := newGatedWriter()
+		c := base.WithOptions(sloglambda.WithWriter(out), sloglambda.WithAsync(4, sloglambda.Block))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			slog.New(c).Warn("queued")
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("logging waited on the writer")
+		}
+
+		close(out.gate)
+		require.NoError(t, c.Close())
+		assert.Len(t, out.writes, 1)
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		var buf bytes.Buffer
+		base.WithOptions(sloglambda.WithWriter(&buf), sloglambda.WithDiagnostics())
+		assert.Contains(t, buf.String(), `"msg":"logging configured"`)
+	})
+}
+
+type lineRecorder struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (r *lineRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+func TestWithConcurrentWriter(t *testing.T) {
+	out := new(lineRecorder)
+	logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON(), sloglambda.WithConcurrentWriter()))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info(t.Name(), "worker", i)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, out.writes, 10)
+	for _, w := range out.writes {
+		assert.True(t, json.Valid([]byte(w)))
+		assert.Equal(t, 1, strings.Count(w, "\n"))
+	}
+}
+
+func TestWithBufferPool(t *testing.T) {
+	for name, option := range map[string]sloglambda.Option{
+		"custom":   sloglambda.WithBufferPool(64<<10, 1<<20),
+		"disabled": sloglambda.WithoutBufferPool(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), option))
+
+			logger.Info("first", "payload", strings.Repeat("x", 32<<10))
+			logger.Info("second")
+
+			lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+			require.Len(t, lines, 2)
+			assert.Contains(t, lines[1], `"msg":"second"`)
+			assert.NotContains(t, lines[1], "payload")
+		})
+	}
+}
+
+func TestWithLevelValue(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.Level(-8)), sloglambda.WithLevelValue()))
+
+	logger.Log(context.Background(), slog.Level(-8), "trace")
+	logger.Log(context.Background(), slog.Level(16), "fatal")
+	logger.Log(context.Background(), slog.LevelError+2, "error")
+	logger.Info("info")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], `"level":"TRACE","levelValue":-8`)
+	assert.Contains(t, lines[1], `"level":"FATAL+4","levelValue":16`)
+	assert.Contains(t, lines[2], `"level":"ERROR+2","levelValue":10`)
+	assert.NotContains(t, lines[3], "levelValue")
+}
+
+func TestWithTextHeader(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithTextHeader("time", "level", "msg", "missing")))
+
+	logger.Info("Hello, world!", "alpha", 1)
+
+	assert.Regexp(t, `^time=\S+ level="INFO" msg="Hello, world!" alpha=1 record\.functionName="test-function" record\.version="\$LATEST" type="app\.log"\n$`, buffer.String())
+}
+
+func TestWithTerminator(t *testing.T) {
+	t.Run("CRLF", func(t *testing.T) {
+		for _, option := range []sloglambda.Option{sloglambda.WithJSON(), sloglambda.WithText()} {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, option, sloglambda.WithTerminator("\r\n")))
+
+			logger.Info("multi\r\nline", "value", "carriage\rreturn")
+			logger.Info("second")
+
+			lines := strings.Split(buffer.String(), "\r\n")
+			require.Len(t, lines, 3)
+			assert.Empty(t, lines[2])
+			assert.NotContains(t, lines[0], "\r")
+			assert.NotContains(t, lines[0], "\n")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, terminator := range []string{"", "\r", "\n\r"} {
+			_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithTerminator(terminator))
+			assert.ErrorIs(t, err, sloglambda.ErrInvalidOption, "%q", terminator)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	kHeartbeatElapsed   = "heartbeatMs"
+	kHeartbeatProcessed = "processed"
+	kHeartbeatRemaining = "remainingMs"
+)
+
+// DefaultHeartbeatInterval is the interval used by StartHeartbeat when none is given.
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// StartHeartbeat logs a "heartbeat" record every interval until the returned function is called or
+// ctx is done, so a long running invocation can be told apart from one that hangs.
+//
+// Each record has the "heartbeatMs" since the heartbeat started, the number of items "processed"
+// reported by progress when it is not nil, and the "remainingMs" before the deadline of ctx when it
+// has one. Records are written with the logger from ctx (see LoggerFromContext).
+//
+// The returned function stops the heartbeat and waits for it to exit; it is safe to call more than
+// once. An interval of zero or less uses DefaultHeartbeatInterval.
+func StartHeartbeat(ctx context.Context, interval time.Duration, progress func() int64) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	var (
+		started = time.Now()
+		logger  = LoggerFromContext(ctx)
+		done    = make(chan struct{})
+		exited  = make(chan struct{})
+	)
+
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				attrs := []slog.Attr{slog.Int64(kHeartbeatElapsed, now.Sub(started).Milliseconds())}
+				if progress != nil {
+					attrs = append(attrs, slog.Int64(kHeartbeatProcessed, progress()))
+				}
+				if deadline, ok := ctx.Deadline(); ok {
+					attrs = append(attrs, slog.Int64(kHeartbeatRemaining, deadline.Sub(now).Milliseconds()))
+				}
+				logger.LogAttrs(ctx, slog.LevelInfo, "heartbeat", attrs...)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
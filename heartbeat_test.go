@@ -0,0 +1,99 @@
+package sloglambda_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	t.Run("logs periodically", func(t *testing.T) {
+		out := new(lineRecorder)
+		logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON()))
+
+		ctx, cancel := context.WithTimeout(sloglambda.ContextWithLogger(context.Background(), logger), time.Minute)
+		defer cancel()
+
+		var processed atomic.Int64
+		processed.Store(7)
+
+		stop := sloglambda.StartHeartbeat(ctx, time.Millisecond, processed.Load)
+		assert.Eventually(t, func() bool {
+			out.mu.Lock()
+			defer out.mu.Unlock()
+			return len(out.writes) >= 2
+		}, time.Second, time.Millisecond)
+		stop()
+		stop()
+
+		out.mu.Lock()
+		defer out.mu.Unlock()
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out.writes[0]), &record))
+		assert.Equal(t, "heartbeat", record["msg"])
+		assert.Equal(t, float64(7), record["processed"])
+		assert.Contains(t, record, "heartbeatMs")
+		assert.Greater(t, record["remainingMs"], float64(0))
+	})
+
+	t.Run("does not collide with the elapsed field", func(t *testing.T) {
+		out := new(lineRecorder)
+		logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON(), sloglambda.WithElapsed()))
+
+		start := time.Now().Add(-time.Hour)
+		ctx := sloglambda.ContextWithInvocationStart(sloglambda.ContextWithLogger(context.Background(), logger), start)
+
+		stop := sloglambda.StartHeartbeat(ctx, time.Millisecond, nil)
+		assert.Eventually(t, func() bool {
+			out.mu.Lock()
+			defer out.mu.Unlock()
+			return len(out.writes) >= 1
+		}, time.Second, time.Millisecond)
+		stop()
+
+		out.mu.Lock()
+		defer out.mu.Unlock()
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out.writes[0]), &record))
+		assert.GreaterOrEqual(t, record["elapsedMs"], float64(time.Hour.Milliseconds()))
+		assert.Less(t, record["heartbeatMs"], float64(time.Hour.Milliseconds()))
+	})
+
+	t.Run("stops with the context", func(t *testing.T) {
+		out := new(lineRecorder)
+		logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON()))
+
+		ctx, cancel := context.WithCancel(sloglambda.ContextWithLogger(context.Background(), logger))
+		stop := sloglambda.StartHeartbeat(ctx, time.Hour, nil)
+		cancel()
+		stop()
+
+		assert.Empty(t, out.writes)
+	})
+
+	t.Run("omits unavailable fields", func(t *testing.T) {
+		out := new(lineRecorder)
+		logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON()))
+
+		stop := sloglambda.StartHeartbeat(sloglambda.ContextWithLogger(context.Background(), logger), time.Millisecond, nil)
+		assert.Eventually(t, func() bool {
+			out.mu.Lock()
+			defer out.mu.Unlock()
+			return len(out.writes) >= 1
+		}, time.Second, time.Millisecond)
+		stop()
+
+		assert.False(t, strings.Contains(out.writes[0], "processed"))
+		assert.False(t, strings.Contains(out.writes[0], "remainingMs"))
+	})
+}
@@ -0,0 +1,122 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+var (
+	kHTTPGroup   = "http"
+	kHTTPMethod  = "method"
+	kHTTPPath    = "path"
+	kHTTPStatus  = "status"
+	kHTTPBytes   = "bytes"
+	kHTTPLatency = "latency"
+)
+
+// invocationEnder is implemented by handlers that keep per-invocation state, such as *Handler.
+type invocationEnder interface {
+	EndInvocation(ctx context.Context) error
+}
+
+// HTTPMiddleware returns net/http middleware for functions served through Lambda function URLs
+// (for example with lambdaurl) or any other http.Handler based runtime.
+//
+// For each request a child of logger carrying the "requestId" and the request method and path is
+// stored in the request context, where it can be retrieved with LoggerFromContext. The request ID is
+// the Lambda request ID when available, the X-Request-Id header otherwise, or a randomly generated
+// UUID. W3C traceparent, tracestate, and baggage headers are stored in the request context as well
+// (see WithTraceContext). Once the request completes an AccessLog with the same request ID is
+// written, and when the logger's handler is a *Handler the invocation is ended (see
+// Handler.EndInvocation).
+//
+// The ResponseWriter passed to the next handler supports http.Flusher and http.ResponseController
+// when the original ResponseWriter does, so streamed responses keep working.
+func HTTPMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := ContextWithInvocationStart(r.Context(), start)
+
+			if traceparent := r.Header.Get("traceparent"); traceparent != "" {
+				ctx, _ = ContextWithTraceContext(ctx, traceparent, r.Header.Get("tracestate"))
+			}
+			if baggage := r.Header.Get("baggage"); baggage != "" {
+				ctx, _ = ContextWithBaggage(ctx, baggage)
+			}
+
+			requestID := requestIDFromContext(ctx)
+			if requestID == "" {
+				requestID = r.Header.Get("X-Request-Id")
+			}
+			if requestID == "" {
+				requestID = newUUID()
+			}
+
+			requestLogger := logger.With(slog.String(kRequestID, requestID), slog.Group(kHTTPGroup,
+				slog.String(kHTTPMethod, r.Method),
+				slog.String(kHTTPPath, r.URL.Path),
+			))
+			ctx = ContextWithLogger(ctx, requestLogger)
+
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			access := NewAccessLog(r)
+			access.RequestID = requestID
+			access.Status = recorder.status()
+			access.Bytes = int64(recorder.bytes)
+			access.Latency = time.Since(start)
+			access.Log(ctx, logger)
+
+			if ender, ok := logger.Handler().(invocationEnder); ok {
+				_ = ender.EndInvocation(ctx)
+			}
+		})
+	}
+}
+
+// statusRecorder captures the status code and number of bytes written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	code  int
+	bytes int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush sends any buffered data to the client, when the underlying ResponseWriter supports it.
+func (r *statusRecorder) Flush() {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+func (r *statusRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+// Unwrap returns the underlying ResponseWriter, for use by http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,105 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	handler := sloglambda.HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sloglambda.LoggerFromContext(r.Context()).InfoContext(r.Context(), "handling")
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+	request := httptest.NewRequest(http.MethodPost, "/orders", nil).WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+
+	assert.Contains(t, lines[0], `"msg":"handling"`)
+	assert.Contains(t, lines[0], `"http":{"method":"POST","path":"/orders"}`)
+	assert.Contains(t, lines[0], `"requestId":"abc-123"`)
+
+	assert.Contains(t, lines[1], `"msg":"request completed"`)
+	assert.Contains(t, lines[1], `"method":"POST"`)
+	assert.Contains(t, lines[1], `"status":201`)
+	assert.Contains(t, lines[1], `"bytes":7`)
+	assert.Contains(t, lines[1], `"latency":`)
+	assert.Contains(t, lines[1], `"type":"access.log"`)
+}
+
+func TestHTTPMiddleware_requestID(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	handler := sloglambda.HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sloglambda.LoggerFromContext(r.Context()).InfoContext(r.Context(), "handling")
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set("X-Request-Id", "req-9")
+
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+
+	assert.Contains(t, lines[0], `"requestId":"req-9"`)
+	assert.Contains(t, lines[1], `"requestId":"req-9"`)
+}
+
+func TestHTTPMiddleware_flush(t *testing.T) {
+	logger := slog.New(sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithJSON()))
+
+	handler := sloglambda.HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+
+		w.Write([]byte("chunk"))
+		flusher.Flush()
+
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	assert.True(t, response.Flushed)
+	assert.Equal(t, "chunk", response.Body.String())
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	t.Run("without a logger", func(t *testing.T) {
+		assert.Same(t, slog.Default(), sloglambda.LoggerFromContext(context.Background()))
+	})
+
+	t.Run("with a logger", func(t *testing.T) {
+		logger := slog.New(sloglambda.NewHandler(new(bytes.Buffer)))
+		ctx := sloglambda.ContextWithLogger(context.Background(), logger)
+
+		assert.Same(t, logger, sloglambda.LoggerFromContext(ctx))
+	})
+}
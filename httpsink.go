@@ -0,0 +1,266 @@
+package sloglambda
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSinkBatchSize is the default number of records an HTTP sink buffers before sending.
+	DefaultSinkBatchSize = 100
+	// DefaultSinkTimeout is the default time an HTTP sink waits for a batch to be sent.
+	DefaultSinkTimeout = 5 * time.Second
+)
+
+// httpBatchRetained is the number of batches an HTTP sink keeps buffered while sends fail, before it
+// drops the oldest records.
+const httpBatchRetained = 10
+
+// httpRecord is a record buffered by an HTTP sink, along with its decoded fields.
+type httpRecord struct {
+	raw    []byte
+	fields map[string]any
+}
+
+// partialSendError is returned by the send function of an httpBatch when the endpoint accepted
+// some of the records it was sent. Only the records in retry are buffered again; the others were
+// either accepted or rejected permanently.
+type partialSendError struct {
+	retry    []httpRecord
+	rejected int
+	err      error
+}
+
+func (e *partialSendError) Error() string {
+	return e.err.Error()
+}
+
+func (e *partialSendError) Unwrap() error {
+	return e.err
+}
+
+// httpBatch buffers the records written to an HTTP sink and sends them in batches.
+//
+// A full batch is sent from a background goroutine, so writing a record never waits on the network.
+// Records stay buffered until they are sent successfully and are retried with the next batch; once
+// more than httpBatchRetained batches are buffered the oldest records are dropped and reported.
+// Records the endpoint rejects permanently are reported by the next write or Flush.
+type httpBatch struct {
+	name      string
+	client    *http.Client
+	batchSize int
+	timeout   time.Duration
+	send      func(ctx context.Context, records []httpRecord) error
+
+	gzip        bool
+	gzipMinSize int
+
+	sending  sync.Mutex
+	inflight sync.WaitGroup
+
+	mu       sync.Mutex
+	records  []httpRecord
+	busy     bool
+	dropped  int
+	rejected []error
+}
+
+func newHTTPBatch(name string, send func(ctx context.Context, records []httpRecord) error) *httpBatch {
+	return &httpBatch{
+		name:      name,
+		client:    http.DefaultClient,
+		batchSize: DefaultSinkBatchSize,
+		timeout:   DefaultSinkTimeout,
+		send:      send,
+	}
+}
+
+// write buffers the newline terminated records in p, starting a send once the batch is full.
+// Records that are not JSON objects are skipped and reported, without affecting the rest of p.
+func (b *httpBatch) write(p []byte) (int, error) {
+	var errs []error
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+			errs = append(errs, fmt.Errorf("%s: skipped invalid record: %q", b.name, line[:min(len(line), 64)]))
+			continue
+		}
+		b.records = append(b.records, httpRecord{raw: bytes.Clone(line), fields: fields})
+	}
+	b.trim()
+
+	if len(b.records) >= b.batchSize && !b.busy {
+		b.busy = true
+		b.inflight.Add(1)
+		go func() {
+			defer b.inflight.Done()
+			err := b.sendBuffered()
+
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			b.busy = false
+			var partial *partialSendError
+			if errors.As(err, &partial) && partial.rejected > 0 {
+				b.rejected = append(b.rejected, err)
+			}
+		}()
+	}
+
+	return len(p), errors.Join(append(errs, b.takeErrors())...)
+}
+
+// Flush waits for a send in progress, then sends the buffered records.
+func (b *httpBatch) Flush() error {
+	b.inflight.Wait()
+	err := b.sendBuffered()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return errors.Join(err, b.takeErrors())
+}
+
+// sendBuffered sends the records buffered when it is called. If the send fails they are put back
+// in front of the records buffered since, or only those that can be retried when the endpoint
+// accepted some of them.
+func (b *httpBatch) sendBuffered() error {
+	b.sending.Lock()
+	defer b.sending.Unlock()
+
+	b.mu.Lock()
+	records := b.records
+	b.records = nil
+	b.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	err := b.send(ctx, records)
+	if err != nil {
+		var partial *partialSendError
+		if errors.As(err, &partial) {
+			records = partial.retry
+		}
+
+		b.mu.Lock()
+		b.records = append(records, b.records...)
+		b.trim()
+		b.mu.Unlock()
+	}
+	return err
+}
+
+// trim drops the oldest buffered records beyond the number the batch retains. It must be called
+// with b.mu held.
+func (b *httpBatch) trim() {
+	if n := len(b.records) - max(b.batchSize, 1)*httpBatchRetained; n > 0 {
+		b.records = slices.Delete(b.records, 0, n)
+		b.dropped += n
+	}
+}
+
+// takeErrors returns an error reporting the records rejected by background sends and the records
+// dropped since it was last called, if any. It must be called with b.mu held.
+func (b *httpBatch) takeErrors() error {
+	errs := b.rejected
+	b.rejected = nil
+	if b.dropped > 0 {
+		errs = append(errs, fmt.Errorf("%s: dropped %d records after failed sends", b.name, b.dropped))
+		b.dropped = 0
+	}
+	return errors.Join(errs...)
+}
+
+// withGzip configures the batch to gzip compress payloads of at least minSize bytes.
+func (b *httpBatch) withGzip(minSize int) {
+	b.gzip = true
+	b.gzipMinSize = max(minSize, 0)
+}
+
+// newRequest creates a POST request sending body to url, gzip compressed if the batch is configured
+// to compress a payload of its size. It also returns the body as sent.
+func (b *httpBatch) newRequest(ctx context.Context, url string, body []byte) (*http.Request, []byte, error) {
+	encoding := ""
+	if b.gzip && len(body) >= b.gzipMinSize {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(body); err != nil {
+			return nil, nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, nil, err
+		}
+		body, encoding = compressed.Bytes(), "gzip"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	return req, body, nil
+}
+
+// do sends the request and returns the response body, or an error naming the sink if the response
+// is not successful.
+func (b *httpBatch) do(sink string, req *http.Request) ([]byte, error) {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sink, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s: unexpected response %s: %s", sink, resp.Status, bytes.TrimSpace(body[:min(len(body), 1<<10)]))
+	}
+	return body, nil
+}
+
+// recordTime returns the time of a decoded record, or the current time if it has none.
+func recordTime(fields map[string]any) time.Time {
+	for _, key := range []string{slog.TimeKey, kInsightsTimestamp} {
+		if value, ok := fields[key].(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Now()
+}
+
+// recordInvocation returns the request ID and function name of the decoded record.
+func recordInvocation(fields map[string]any) (requestID, function string) {
+	requestID, _ = fields[kLambdaRequestId].(string)
+	if lambda, ok := fields[kLambdaRecord].(map[string]any); ok {
+		if id, ok := lambda[kLambdaRequestId].(string); ok {
+			requestID = id
+		}
+		function, _ = lambda[kLambdaFunctionName].(string)
+	}
+	return requestID, function
+}
@@ -0,0 +1,189 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSinkGzip(t *testing.T) {
+	type request struct {
+		encoding string
+		body     string
+	}
+
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			body, err = io.ReadAll(zr)
+			require.NoError(t, err)
+		}
+		requests = append(requests, request{r.Header.Get("Content-Encoding"), string(body)})
+		w.Write([]byte(`{"errors":false}`))
+	}))
+	defer server.Close()
+
+	small := `{"msg":"small"}` + "\n"
+	large := `{"msg":"` + strings.Repeat("x", 1024) + `"}` + "\n"
+
+	t.Run("compresses payloads over the minimum size", func(t *testing.T) {
+		requests = nil
+		w := sloglambda.NewLokiWriter(server.URL).WithGzip(512)
+
+		for _, record := range []string{small, large} {
+			_, err := w.Write([]byte(record))
+			require.NoError(t, err)
+			require.NoError(t, w.Flush())
+		}
+
+		require.Len(t, requests, 2)
+		assert.Empty(t, requests[0].encoding)
+		assert.Equal(t, "gzip", requests[1].encoding)
+		assert.Contains(t, requests[1].body, strings.Repeat("x", 1024))
+	})
+
+	t.Run("signs the compressed body", func(t *testing.T) {
+		requests = nil
+		var signed []byte
+		w := sloglambda.NewOpenSearchWriter(server.URL).WithGzip(0).WithSigner(func(_ *http.Request, body []byte) error {
+			signed = body
+			return nil
+		})
+
+		_, err := w.Write([]byte(small))
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "gzip", requests[0].encoding)
+		assert.Equal(t, []byte{0x1f, 0x8b}, signed[:2])
+	})
+
+	t.Run("every sink", func(t *testing.T) {
+		for name, w := range map[string]interface {
+			io.Writer
+			Flush() error
+		}{
+			"otlp":   sloglambda.NewOTLPWriter(server.URL).WithGzip(0),
+			"splunk": sloglambda.NewSplunkWriter(server.URL, "token").WithGzip(0),
+		} {
+			requests = nil
+			_, err := w.Write([]byte(small))
+			require.NoError(t, err)
+			require.NoError(t, w.Flush())
+
+			require.Len(t, requests, 1, name)
+			assert.Equal(t, "gzip", requests[0].encoding, name)
+		}
+	})
+}
+
+func TestHTTPSinkDelivery(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		failing   bool
+		delivered []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var push struct {
+			Streams []struct {
+				Values [][2]string `json:"values"`
+			} `json:"streams"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&push))
+		for _, stream := range push.Streams {
+			for _, value := range stream.Values {
+				delivered = append(delivered, value[1])
+			}
+		}
+	}))
+	defer server.Close()
+
+	reset := func(fail bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		failing, delivered = fail, nil
+	}
+
+	t.Run("keeps records until they are sent", func(t *testing.T) {
+		reset(true)
+		w := sloglambda.NewLokiWriter(server.URL)
+
+		_, err := w.Write([]byte(`{"msg":"kept"}` + "\n"))
+		require.NoError(t, err)
+		require.Error(t, w.Flush())
+
+		reset(false)
+		require.NoError(t, w.Flush())
+		assert.Equal(t, []string{`{"msg":"kept"}`}, delivered)
+	})
+
+	t.Run("skips invalid records", func(t *testing.T) {
+		reset(false)
+		w := sloglambda.NewLokiWriter(server.URL)
+
+		_, err := w.Write([]byte(`{"msg":"a"}` + "\nnot json\n" + `{"msg":"b"}` + "\n"))
+		assert.ErrorContains(t, err, `loki: skipped invalid record: "not json"`)
+
+		require.NoError(t, w.Flush())
+		assert.Equal(t, []string{`{"msg":"a"}`, `{"msg":"b"}`}, delivered)
+	})
+
+	t.Run("drops the oldest records while sends fail", func(t *testing.T) {
+		reset(true)
+		w := sloglambda.NewLokiWriter(server.URL).WithBatchSize(1)
+
+		var errs []error
+		for i := range 15 {
+			_, err := w.Write([]byte(`{"msg":"` + strconv.Itoa(i) + `"}` + "\n"))
+			errs = append(errs, err)
+		}
+		errs = append(errs, w.Flush())
+		assert.ErrorContains(t, errors.Join(errs...), "loki: dropped")
+
+		reset(false)
+		require.NoError(t, w.Flush())
+		require.Len(t, delivered, 10)
+		assert.Equal(t, `{"msg":"5"}`, delivered[0])
+		assert.Equal(t, `{"msg":"14"}`, delivered[9])
+	})
+
+	t.Run("sends full batches without blocking the writer", func(t *testing.T) {
+		reset(false)
+		gate := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			<-gate
+		}))
+		defer slow.Close()
+
+		w := sloglambda.NewLokiWriter(slow.URL).WithBatchSize(1)
+		_, err := w.Write([]byte(`{"msg":"x"}` + "\n"))
+		require.NoError(t, err)
+
+		close(gate)
+		require.NoError(t, w.Flush())
+	})
+}
@@ -0,0 +1,34 @@
+package sloglambda
+
+import "log/slog"
+
+var (
+	kInsightsTimestamp = "@timestamp"
+	kInsightsMessage   = "@message"
+)
+
+// WithInsightsFields configures the Handler to use field names that CloudWatch Logs Insights
+// discovers automatically.
+//
+// The record time is written as "@timestamp", the message as "@message", and the request ID is
+// written as a top-level "requestId" field instead of inside the lambda record, matching the shape
+// of Lambda's native JSON logs so existing queries work unchanged.
+func WithInsightsFields() Option {
+	return func(h *Handler) {
+		h.insights = true
+	}
+}
+
+func (h *Handler) messageKey() string {
+	if h.insights {
+		return kInsightsMessage
+	}
+	return slog.MessageKey
+}
+
+func (h *Handler) timeKey() string {
+	if h.insights {
+		return kInsightsTimestamp
+	}
+	return slog.TimeKey
+}
@@ -0,0 +1,36 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInsightsFields(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithInsightsFields()))
+
+	logger.InfoContext(ctx, t.Name())
+
+	result := make(map[string]any)
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &result))
+
+	assert.Equal(t, t.Name(), result["@message"])
+	assert.Contains(t, result, "@timestamp")
+	assert.Equal(t, "INFO", result["level"])
+	assert.Equal(t, "abc-123", result["requestId"])
+	assert.NotContains(t, result, "msg")
+	assert.NotContains(t, result, "time")
+	assert.NotContains(t, result["record"], "requestId")
+}
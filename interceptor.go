@@ -0,0 +1,66 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Interceptor inspects a record before the Handler processes it, and may change its message or
+// level or add attributes to it, for example to classify records by the content of their message.
+type Interceptor func(ctx context.Context, record *slog.Record)
+
+// WithInterceptor configures the Handler to pass every record through the given interceptors, in
+// order, before it is filtered, sampled, and encoded.
+//
+// The option can be given more than once. Interceptors only see records at or above the level of
+// the Handler.
+func WithInterceptor(interceptors ...Interceptor) Option {
+	return func(h *Handler) {
+		for _, i := range interceptors {
+			if i == nil {
+				h.invalidOption("WithInterceptor: nil interceptor")
+				return
+			}
+		}
+		h.interceptors = append(h.interceptors[:len(h.interceptors):len(h.interceptors)], interceptors...)
+	}
+}
+
+// intercept passes the record through the Handler's interceptors.
+func (h *Handler) intercept(ctx context.Context, record *slog.Record) {
+	for _, i := range h.interceptors {
+		i(ctx, record)
+	}
+}
+
+// MessageClass assigns a class to the records whose message contains a substring.
+type MessageClass struct {
+	Contains string
+	Class    string
+}
+
+// ClassifyMessages returns an Interceptor that adds an attribute with the given key to records
+// whose message contains one of the substrings of classes, ignoring case. The value is the class of
+// the first match:
+//
+//	sloglambda.WithInterceptor(sloglambda.ClassifyMessages("errorClass",
+//		sloglambda.MessageClass{Contains: "timeout", Class: "timeout"},
+//		sloglambda.MessageClass{Contains: "throttl", Class: "throttle"},
+//	))
+func ClassifyMessages(key string, classes ...MessageClass) Interceptor {
+	lowered := make([]MessageClass, len(classes))
+	for i, c := range classes {
+		lowered[i] = MessageClass{Contains: strings.ToLower(c.Contains), Class: c.Class}
+	}
+
+	return func(_ context.Context, record *slog.Record) {
+		msg := strings.ToLower(record.Message)
+		for _, c := range lowered {
+			if strings.Contains(msg, c.Contains) {
+				record.AddAttrs(slog.String(key, c.Class))
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInterceptor(t *testing.T) {
+	t.Run("classifies messages", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithInterceptor(
+			sloglambda.ClassifyMessages("errorClass",
+				sloglambda.MessageClass{Contains: "timeout", Class: "timeout"},
+				sloglambda.MessageClass{Contains: "throttl", Class: "throttle"},
+			),
+		)))
+
+		logger.Error("upstream Timeout after 3s")
+		assert.Contains(t, buffer.String(), `"errorClass":"timeout"`)
+
+		buffer.Reset()
+		logger.Warn("request throttled")
+		assert.Contains(t, buffer.String(), `"errorClass":"throttle"`)
+
+		buffer.Reset()
+		logger.Info("ok")
+		assert.NotContains(t, buffer.String(), "errorClass")
+	})
+
+	t.Run("runs in order before filtering", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(),
+			sloglambda.WithSuppress("noisy*"),
+			sloglambda.WithInterceptor(func(_ context.Context, record *slog.Record) {
+				record.Message = "noisy: " + record.Message
+			}),
+			sloglambda.WithInterceptor(func(_ context.Context, record *slog.Record) {
+				if record.Message == "noisy: important" {
+					record.Message = "important"
+				}
+			}),
+		))
+
+		logger.Info("dropped")
+		logger.Info("important")
+
+		assert.NotContains(t, buffer.String(), "dropped")
+		assert.Contains(t, buffer.String(), `"msg":"important"`)
+	})
+
+	t.Run("nil interceptor", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithInterceptor(nil))
+		require.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
@@ -0,0 +1,350 @@
+package sloglambda
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+var kElapsed = "elapsedMs"
+
+// maxTrackedInvocations bounds the number of invocations the tracker will hold state for. It
+// protects against unbounded growth when EndInvocation is never called.
+const maxTrackedInvocations = 64
+
+// requestIDFromContext returns the AWS request ID of the invocation associated with ctx, or an
+// empty string if ctx does not carry a Lambda context.
+func requestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if lc, _ := lambdacontext.FromContext(ctx); lc != nil {
+		return lc.AwsRequestID
+	}
+	return ""
+}
+
+// aliasFromContext returns the alias the function was invoked through, parsed from the qualifier of
+// the invoked function ARN in the Lambda context of ctx. It returns an empty string when the
+// function was invoked without a qualifier, or with a version ("$LATEST" or a version number).
+func aliasFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	lc, _ := lambdacontext.FromContext(ctx)
+	if lc == nil {
+		return ""
+	}
+
+	// arn:aws:lambda:<region>:<account>:function:<name>[:<qualifier>]
+	parts := strings.Split(lc.InvokedFunctionArn, ":")
+	if len(parts) != 8 || parts[5] != "function" {
+		return ""
+	}
+	qualifier := parts[7]
+	if qualifier == "$LATEST" || strings.Trim(qualifier, "0123456789") == "" {
+		return ""
+	}
+	return qualifier
+}
+
+// invocation holds the state accumulated by a Handler over the course of a single invocation.
+type invocation struct {
+	mu         sync.Mutex
+	started    time.Time
+	records    int
+	bytes      int
+	suppressed int
+	errored    bool
+	messages   map[string]*messageCount
+	silenced   map[string]int
+}
+
+func (i *invocation) record(level slog.Level, n int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.records++
+	i.bytes += n
+	if level >= slog.LevelError {
+		i.errored = true
+	}
+}
+
+type invocationTracker struct {
+	mu    sync.Mutex
+	state map[string]*invocation
+}
+
+func newInvocationTracker() *invocationTracker {
+	return &invocationTracker{
+		state: make(map[string]*invocation),
+	}
+}
+
+// get returns the state for the given request ID, creating it if needed.
+func (t *invocationTracker) get(requestID string) *invocation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if inv, ok := t.state[requestID]; ok {
+		return inv
+	}
+
+	if len(t.state) >= maxTrackedInvocations {
+		t.evictOldest()
+	}
+
+	inv := &invocation{started: time.Now()}
+	t.state[requestID] = inv
+	return inv
+}
+
+// started returns the time the state for the given request ID was created, without creating it.
+func (t *invocationTracker) started(requestID string) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if inv, ok := t.state[requestID]; ok {
+		return inv.started, true
+	}
+	return time.Time{}, false
+}
+
+// remove releases the state for the given request ID, returning it if it existed.
+func (t *invocationTracker) remove(requestID string) *invocation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	inv := t.state[requestID]
+	delete(t.state, requestID)
+	return inv
+}
+
+func (t *invocationTracker) evictOldest() {
+	var (
+		oldestID string
+		oldest   *invocation
+	)
+	for id, inv := range t.state {
+		if oldest == nil || inv.started.Before(oldest.started) {
+			oldestID, oldest = id, inv
+		}
+	}
+	delete(t.state, oldestID)
+}
+
+type invocationBudget struct {
+	maxRecords int
+	maxBytes   int
+}
+
+func (b *invocationBudget) exceeded(inv *invocation) bool {
+	return (b.maxRecords > 0 && inv.records >= b.maxRecords) || (b.maxBytes > 0 && inv.bytes >= b.maxBytes)
+}
+
+// WithDeadlineGuard configures the Handler to drop DEBUG and TRACE records once the time remaining
+// before the context deadline drops below threshold.
+//
+// This reserves the final moments of an invocation that is about to time out for the WARN and
+// ERROR records that help diagnose the timeout.
+func WithDeadlineGuard(threshold time.Duration) Option {
+	return func(h *Handler) {
+		if threshold < 0 {
+			h.invalidOption("WithDeadlineGuard: negative threshold %s", threshold)
+		}
+		h.deadlineGuard = threshold
+	}
+}
+
+// nearDeadline reports whether the deadline of ctx is closer than the Handler's deadline guard.
+func (h *Handler) nearDeadline(ctx context.Context) bool {
+	if h.deadlineGuard <= 0 || ctx == nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < h.deadlineGuard
+}
+
+// admit reports whether the record should be written for the given invocation.
+func (h *Handler) admit(ctx context.Context, inv *invocation, record slog.Record) bool {
+	if record.Level < slog.LevelInfo && h.nearDeadline(ctx) {
+		return false
+	}
+
+	if record.Level >= slog.LevelWarn || (h.budget == nil && h.messageSampling == nil) {
+		return true
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if h.messageSampling != nil {
+		if inv.messages == nil {
+			inv.messages = make(map[string]*messageCount)
+		}
+		count, ok := inv.messages[record.Message]
+		if !ok {
+			count = new(messageCount)
+			inv.messages[record.Message] = count
+		}
+		if !h.messageSampling.sample(count) {
+			return false
+		}
+	}
+
+	if h.budget != nil && h.budget.exceeded(inv) {
+		inv.suppressed++
+		return false
+	}
+	return true
+}
+
+// summaries returns the records summarizing the invocation, to be written when it ends.
+func (inv *invocation) summaries() []slog.Record {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	var records []slog.Record
+
+	messages := make([]string, 0, len(inv.messages))
+	for msg, count := range inv.messages {
+		if count.seen > count.written {
+			messages = append(messages, msg)
+		}
+	}
+	slices.Sort(messages)
+
+	for _, msg := range messages {
+		record := slog.NewRecord(time.Now(), slog.LevelInfo, "message sampled", 0)
+		record.AddAttrs(
+			slog.String("sampled", msg),
+			slog.Int("total", inv.messages[msg].seen),
+			slog.Int("written", inv.messages[msg].written),
+		)
+		records = append(records, record)
+	}
+
+	patterns := make([]string, 0, len(inv.silenced))
+	for pattern := range inv.silenced {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+
+	for _, pattern := range patterns {
+		record := slog.NewRecord(time.Now(), slog.LevelInfo, "messages suppressed", 0)
+		record.AddAttrs(
+			slog.String("pattern", pattern),
+			slog.Int("suppressed", inv.silenced[pattern]),
+		)
+		records = append(records, record)
+	}
+
+	if inv.suppressed > 0 {
+		record := slog.NewRecord(time.Now(), slog.LevelWarn, "log budget exceeded", 0)
+		record.AddAttrs(slog.Int("suppressed", inv.suppressed))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// EndInvocation signals that the invocation associated with ctx has finished.
+//
+// Any per-invocation summary records are written, the sink is flushed (see Sink), and the state the
+// Handler kept for the invocation is released. It is safe to call EndInvocation when no records were
+// logged; the invocation is still counted in the "invocation" number of the lambda record.
+func (h *Handler) EndInvocation(ctx context.Context) error {
+	executionEnvironment.observe(requestIDFromContext(ctx))
+
+	if start, ok := h.invocationStart(ctx); ok {
+		ctx = ContextWithInvocationStart(ctx, start)
+	}
+	ctx = context.WithValue(ctx, invocationEndKey{}, time.Now())
+
+	inv := h.invocations.remove(requestIDFromContext(ctx))
+	if inv == nil {
+		if h.adaptive != nil {
+			h.adaptive.Observe(false)
+		}
+		return h.flush(ctx)
+	}
+
+	inv.mu.Lock()
+	errored := inv.errored
+	inv.mu.Unlock()
+
+	if h.adaptive != nil {
+		h.adaptive.Observe(errored)
+	}
+
+	var errs []error
+	for _, record := range inv.summaries() {
+		if _, err := h.emit(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(append(errs, h.flush(ctx))...)
+}
+
+// flush writes the records queued by an asynchronous Handler, then flushes the sink and outputs.
+// Errors are also reported to the error handler, as callers such as HTTPMiddleware discard them.
+func (h *Handler) flush(ctx context.Context) error {
+	if h.async != nil {
+		h.async.flush()
+	}
+	err := errors.Join(h.sink.Flush(ctx), h.flushOutputs(ctx))
+	h.reportError(ctx, err)
+	return err
+}
+
+type invocationStartKey struct{}
+
+// invocationEndKey records the time the invocation ended in the context EndInvocation flushes the
+// sink with.
+type invocationEndKey struct{}
+
+// ContextWithInvocationStart returns a copy of ctx recording the time the invocation started.
+//
+// HTTPMiddleware records the start of each request. Without it, the Handler uses the time it first
+// saw a record for the invocation's request ID.
+func ContextWithInvocationStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, invocationStartKey{}, start)
+}
+
+// WithElapsed configures the Handler to include an "elapsedMs" field with the number of
+// milliseconds since the invocation started (see ContextWithInvocationStart).
+//
+// The field is omitted for records that are not associated with an invocation.
+func WithElapsed() Option {
+	return func(h *Handler) {
+		h.elapsed = true
+	}
+}
+
+// invocationEnd returns the time the invocation ended, if ctx is the context of an ended
+// invocation.
+func invocationEnd(ctx context.Context) (time.Time, bool) {
+	end, ok := ctx.Value(invocationEndKey{}).(time.Time)
+	return end, ok
+}
+
+// invocationStart returns the start time of the invocation associated with ctx.
+func (h *Handler) invocationStart(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	if start, ok := ctx.Value(invocationStartKey{}).(time.Time); ok {
+		return start, true
+	}
+	if requestID := requestIDFromContext(ctx); requestID != "" {
+		return h.invocations.started(requestID)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,185 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_EndInvocation(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+
+	t.Run("without any records", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON())
+
+		require.NoError(t, handler.EndInvocation(ctx))
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestWithInvocationBudget(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+
+	t.Run("records", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithInvocationBudget(2, 0))
+		logger := slog.New(handler)
+
+		for i := 0; i < 5; i++ {
+			logger.InfoContext(ctx, "info")
+		}
+		logger.WarnContext(ctx, "warn")
+
+		require.NoError(t, handler.EndInvocation(ctx))
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Contains(t, lines[2], `"msg":"warn"`)
+		assert.Contains(t, lines[3], `"msg":"log budget exceeded"`)
+		assert.Contains(t, lines[3], `"suppressed":3`)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithInvocationBudget(0, 1))
+		logger := slog.New(handler)
+
+		logger.InfoContext(ctx, "first")
+		logger.InfoContext(ctx, "second")
+
+		assert.Contains(t, buffer.String(), `"msg":"first"`)
+		assert.NotContains(t, buffer.String(), `"msg":"second"`)
+	})
+
+	t.Run("is tracked per invocation", func(t *testing.T) {
+		other := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+			AwsRequestID: "def-456",
+		})
+
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithInvocationBudget(1, 0))
+		logger := slog.New(handler)
+
+		logger.InfoContext(ctx, "first")
+		logger.InfoContext(other, "second")
+
+		assert.Contains(t, buffer.String(), `"msg":"first"`)
+		assert.Contains(t, buffer.String(), `"msg":"second"`)
+	})
+}
+
+func TestWithDeadlineGuard(t *testing.T) {
+	t.Run("when the deadline is near", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug), sloglambda.WithDeadlineGuard(time.Minute)))
+
+		logger.DebugContext(ctx, "debug")
+		logger.WarnContext(ctx, "warn")
+
+		assert.NotContains(t, buffer.String(), `"msg":"debug"`)
+		assert.Contains(t, buffer.String(), `"msg":"warn"`)
+	})
+
+	t.Run("when the deadline is far away", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug), sloglambda.WithDeadlineGuard(time.Minute)))
+
+		logger.DebugContext(ctx, "debug")
+
+		assert.Contains(t, buffer.String(), `"msg":"debug"`)
+	})
+}
+
+func TestWithElapsed(t *testing.T) {
+	t.Run("with an invocation start", func(t *testing.T) {
+		ctx := sloglambda.ContextWithInvocationStart(context.Background(), time.Now().Add(-1500*time.Millisecond))
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithElapsed()))
+
+		logger.InfoContext(ctx, t.Name())
+
+		assert.Regexp(t, `"elapsedMs":15\d\d`, buffer.String())
+	})
+
+	t.Run("with a lambda context", func(t *testing.T) {
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+			AwsRequestID: "abc-123",
+		})
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithElapsed()))
+
+		logger.InfoContext(ctx, t.Name())
+
+		assert.Contains(t, buffer.String(), `"elapsedMs":0`)
+	})
+
+	t.Run("without an invocation", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithElapsed()))
+
+		logger.Info(t.Name())
+
+		assert.NotContains(t, buffer.String(), `"elapsedMs"`)
+	})
+}
+
+func TestAlias(t *testing.T) {
+	cases := map[string]string{
+		"arn:aws:lambda:us-east-1:123456789012:function:orders:canary":  `"alias":"canary"`,
+		"arn:aws:lambda:us-east-1:123456789012:function:orders:live-v2": `"alias":"live-v2"`,
+		"arn:aws:lambda:us-east-1:123456789012:function:orders:7":       ``,
+		"arn:aws:lambda:us-east-1:123456789012:function:orders:$LATEST": ``,
+		"arn:aws:lambda:us-east-1:123456789012:function:orders":         ``,
+	}
+
+	for arn, want := range cases {
+		t.Run(arn, func(t *testing.T) {
+			ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+				AwsRequestID:       "alias-123",
+				InvokedFunctionArn: arn,
+			})
+
+			buffer := new(bytes.Buffer)
+			slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON())).InfoContext(ctx, t.Name())
+
+			if want == "" {
+				assert.NotContains(t, buffer.String(), `"alias"`)
+			} else {
+				assert.Contains(t, buffer.String(), want)
+			}
+		})
+	}
+
+	t.Run("Enrich", func(t *testing.T) {
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+			AwsRequestID:       "alias-456",
+			InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:orders:canary",
+		})
+
+		buffer := new(bytes.Buffer)
+		slog.New(sloglambda.Enrich(slog.NewJSONHandler(buffer, nil))).InfoContext(ctx, t.Name())
+
+		assert.Contains(t, buffer.String(), `"alias":"canary"`)
+	})
+}
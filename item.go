@@ -0,0 +1,78 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// LogItem logs a record with the per-item attributes in item followed by attrs. It is equivalent to
+//
+//	logger.With(item...).LogAttrs(ctx, level, msg, attrs...)
+//
+// without deriving a logger and Handler for every item, so it suits hot loops:
+//
+//	for _, order := range orders {
+//		sloglambda.LogItem(ctx, logger, slog.LevelInfo, "order shipped", []slog.Attr{slog.Int("orderId", order.ID)})
+//	}
+//
+// It is safe to call concurrently with the same logger.
+func LogItem(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, item []slog.Attr, attrs ...slog.Attr) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	logItem(ctx, logger, level, msg, item, attrs)
+}
+
+// ItemAttrs is a reusable set of per-item attributes. Set replaces the attributes without
+// allocating once the backing slice is large enough, so a single ItemAttrs can be reused for every
+// item of a loop:
+//
+//	var item sloglambda.ItemAttrs
+//	for _, order := range orders {
+//		item.Set(slog.Int("orderId", order.ID), slog.String("status", order.Status))
+//		item.Log(ctx, logger, slog.LevelInfo, "order shipped")
+//	}
+//
+// An ItemAttrs must not be used by several goroutines at once; the logger it logs with can be
+// shared. The zero value is empty and ready to use.
+type ItemAttrs struct {
+	attrs []slog.Attr
+}
+
+// Set replaces the attributes of the item.
+func (a *ItemAttrs) Set(attrs ...slog.Attr) *ItemAttrs {
+	a.attrs = append(a.attrs[:0], attrs...)
+	return a
+}
+
+// Add appends attributes to the item.
+func (a *ItemAttrs) Add(attrs ...slog.Attr) *ItemAttrs {
+	a.attrs = append(a.attrs, attrs...)
+	return a
+}
+
+// Attrs returns the attributes of the item. The slice is reused by the next call to Set.
+func (a *ItemAttrs) Attrs() []slog.Attr {
+	return a.attrs
+}
+
+// Log logs a record with the attributes of the item followed by attrs (see LogItem).
+func (a *ItemAttrs) Log(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	logItem(ctx, logger, level, msg, a.attrs, attrs)
+}
+
+// logItem writes a record with the source of the caller of LogItem or ItemAttrs.Log.
+func logItem(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, item, attrs []slog.Attr) {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.AddAttrs(item...)
+	record.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, record)
+}
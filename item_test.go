@@ -0,0 +1,56 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogItem(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime())).WithGroup("batch")
+
+	sloglambda.LogItem(context.Background(), logger, slog.LevelInfo, "processed", []slog.Attr{slog.Int("id", 1)}, slog.Bool("ok", true))
+	item := buffer.String()
+	buffer.Reset()
+
+	logger.With("id", 1).LogAttrs(context.Background(), slog.LevelInfo, "processed", slog.Bool("ok", true))
+	assert.Equal(t, buffer.String(), item)
+	assert.Contains(t, item, `"batch":{"id":1,"ok":true}`)
+
+	t.Run("source", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSourceFormat(sloglambda.SourceShort)))
+
+		sloglambda.LogItem(context.Background(), logger, slog.LevelInfo, "processed", nil)
+		assert.Regexp(t, `"source":"[^"/]+/item_test\.go:\d+"`, buffer.String())
+	})
+
+	t.Run("disabled level", func(t *testing.T) {
+		buffer.Reset()
+		sloglambda.LogItem(context.Background(), logger, slog.LevelDebug, "hidden", []slog.Attr{slog.Int("id", 1)})
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestItemAttrs(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime()))
+
+	var item sloglambda.ItemAttrs
+	for i := range 3 {
+		item.Set(slog.Int("id", i)).Add(slog.String("status", "done"))
+		item.Log(context.Background(), logger, slog.LevelInfo, "processed", slog.Int("attempt", 1))
+	}
+
+	assert.Equal(t, ""+
+		`{"attempt":1,"id":0,"level":"INFO","msg":"processed","record":{"functionName":"test-function","version":"$LATEST"},"status":"done","type":"app.log"}`+"\n"+
+		`{"attempt":1,"id":1,"level":"INFO","msg":"processed","record":{"functionName":"test-function","version":"$LATEST"},"status":"done","type":"app.log"}`+"\n"+
+		`{"attempt":1,"id":2,"level":"INFO","msg":"processed","record":{"functionName":"test-function","version":"$LATEST"},"status":"done","type":"app.log"}`+"\n",
+		buffer.String())
+	assert.Len(t, item.Attrs(), 2)
+}
@@ -0,0 +1,112 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// maxInternedKeys bounds the number of keys kept in the key cache, so records with unbounded key
+// sets do not grow it forever.
+const maxInternedKeys = 4096
+
+// keyCache holds the JSON encoding of frequently used keys, including the quotes and colon, so
+// they are not escaped again for every record.
+type keyCache struct {
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+var internedKeys = newKeyCache(
+	slog.LevelKey, slog.MessageKey, slog.TimeKey, slog.SourceKey,
+	kInsightsMessage, kInsightsTimestamp,
+	kLambdaRecord, kLambdaFunctionName, kLambdaFunctionVersion, kLambdaRequestId, kLambdaLogType,
+	kLambdaInitializationType, kLambdaInvocation, kLambdaPhase, kSequence, kElapsed,
+)
+
+func newKeyCache(keys ...string) *keyCache {
+	c := &keyCache{keys: make(map[string][]byte, len(keys))}
+	for _, key := range keys {
+		c.keys[key] = encodeKey(key)
+	}
+	return c
+}
+
+func encodeKey(key string) []byte {
+	b, _ := json.Marshal(key)
+	return append(b, ':')
+}
+
+// get returns the encoded key, interning it if intern is set and the cache has room.
+func (c *keyCache) get(key string, intern bool) []byte {
+	c.mu.RLock()
+	encoded, ok := c.keys[key]
+	c.mu.RUnlock()
+	if ok {
+		return encoded
+	}
+
+	encoded = encodeKey(key)
+	if intern {
+		c.mu.Lock()
+		if len(c.keys) < maxInternedKeys {
+			c.keys[key] = encoded
+		}
+		c.mu.Unlock()
+	}
+	return encoded
+}
+
+// intern adds the keys of the attributes, and of the attributes in their groups, to the cache.
+func (c *keyCache) intern(attrs []slog.Attr) {
+	for _, a := range attrs {
+		c.get(a.Key, true)
+		if a.Value.Kind() == slog.KindGroup {
+			c.intern(a.Value.Group())
+		}
+	}
+}
+
+// encodeJSON writes the record to buf as a JSON object followed by a newline, producing the same
+// output as encoding/json.Encoder.
+//
+// Keys are written in sorted order using their cached encoding; only keys already in the cache
+// are reused, keys seen for the first time are encoded without being added to it.
+func encodeJSON(buf *bytes.Buffer, record logRecord) error {
+	if err := encodeJSONObject(buf, record); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+func encodeJSONObject(buf *bytes.Buffer, record logRecord) error {
+	keys := record.keys()
+	slices.Sort(keys)
+
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(internedKeys.get(key, false))
+
+		if group, ok := record[key].(logRecord); ok {
+			if err := encodeJSONObject(buf, group); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b, err := json.Marshal(record[key])
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
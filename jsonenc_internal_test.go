@@ -0,0 +1,65 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_encodeJSON(t *testing.T) {
+	records := map[string]logRecord{
+		"empty": {},
+		"scalars": {
+			"string": "hello",
+			"int":    int64(-42),
+			"uint":   uint64(math.MaxUint64),
+			"float":  3.25,
+			"bool":   true,
+			"nil":    nil,
+			"time":   time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		},
+		"escaping": {
+			"<html> & \"quotes\"": "<script>alert('x')</script> &  ",
+			"control\n":           "tab\t\x01\xff",
+		},
+		"nested": {
+			"record": logRecord{"functionName": "fn", "inner": logRecord{"z": 1, "a": 2}},
+			"map":    map[string]any{"b": 1, "a": []any{"x", 2}},
+			"raw":    json.RawMessage(`{ "spaced" : true }`),
+		},
+	}
+
+	for name, record := range records {
+		t.Run(name, func(t *testing.T) {
+			expected := new(bytes.Buffer)
+			require.NoError(t, json.NewEncoder(expected).Encode(record))
+
+			actual := new(bytes.Buffer)
+			require.NoError(t, encodeJSON(actual, record))
+
+			assert.Equal(t, expected.String(), actual.String())
+		})
+	}
+
+	t.Run("unsupported value", func(t *testing.T) {
+		assert.Error(t, encodeJSON(new(bytes.Buffer), logRecord{"fn": func() {}}))
+	})
+}
+
+func Test_keyCache(t *testing.T) {
+	c := newKeyCache("level")
+	assert.Equal(t, `"level":`, string(c.get("level", false)))
+
+	c.get("seen", false)
+	assert.NotContains(t, c.keys, "seen")
+
+	c.intern([]slog.Attr{slog.Group("request", slog.String("<id>", "1"))})
+	assert.Contains(t, c.keys, "request")
+	assert.Equal(t, `"\u003cid\u003e":`, string(c.keys["<id>"]))
+}
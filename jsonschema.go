@@ -0,0 +1,238 @@
+package sloglambda
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing the records written by the
+// Handler with its current configuration.
+//
+// The document covers the fields the Handler adds itself, the lambda record, attributes added with
+// WithAttrs and WithGroup, and the fields described by the Schema configured with WithSchema.
+// Records may contain additional attributes given at the call site, or added by enrichers given to
+// WithEnrichment, so objects allow additional properties.
+func (h *Handler) JSONSchema() ([]byte, error) {
+	root := newJSONSchemaObject()
+	root["$schema"] = jsonSchemaDialect
+	root["title"] = "log record"
+
+	root.property(slog.LevelKey, FieldString)["pattern"] = `^(TRACE|DEBUG|INFO|WARN|ERROR|FATAL)([+-][0-9]+)?$`
+	root.require(slog.LevelKey)
+
+	root.property(h.messageKey(), FieldString)
+	root.require(h.messageKey())
+
+	if !h.excludeTime {
+		root.property(h.timeKey(), FieldString)["format"] = "date-time"
+	}
+	if h.sequence {
+		root.property(kSequence, FieldInteger)
+	}
+	if h.elapsed {
+		root.property(kElapsed, FieldInteger)
+	}
+	if h.levelValue {
+		root.property(kLevelValue, FieldInteger)
+	}
+	if h.contextError {
+		root.property(kContextError, FieldString)["enum"] = []string{"canceled", "deadline_exceeded"}
+	}
+	if h.snapStart {
+		root.property(kSnapStartRestore, FieldBoolean)
+		root.property(kRestoreLatency, FieldInteger)
+	}
+
+	lambda := kLambdaRecord + "."
+	root.property(lambda+kLambdaFunctionName, FieldString)
+	root.property(lambda+kLambdaFunctionVersion, FieldString)
+	root.property(lambda+kLambdaInitializationType, FieldString)
+	root.property(lambda+kLambdaInvocation, FieldInteger)
+	root.property(lambda+kLambdaAlias, FieldString)
+	if h.insights {
+		root.property(kLambdaRequestId, FieldString)
+	} else {
+		root.property(lambda+kLambdaRequestId, FieldString)
+	}
+	if h.phase {
+		root.property(lambda+kLambdaPhase, FieldString)["enum"] = []string{PhaseInit, PhaseInvoke}
+	}
+
+	logType := root.property(kLambdaLogType, FieldString)
+	if h.logType != "" {
+		logType["default"] = h.logType
+		root.require(kLambdaLogType)
+	}
+
+	root.property(kTenantID, FieldString)
+
+	if h.callerAccount {
+		root.property(kCallerAccount, FieldString)
+		root.property(kClaimedCallerAccount, FieldString)
+	}
+
+	if h.traceContext {
+		root.property(kTraceID, FieldString)
+		root.property(kSpanID, FieldString)
+		root.property(kBaggage, FieldObject)
+	}
+
+	if h.memoryStats {
+		root.property(kMemoryStats+".heapInUse", FieldInteger)
+		root.property(kMemoryStats+".sys", FieldInteger)
+	}
+	if h.goroutineCount {
+		root.property(kGoroutineCount, FieldInteger)
+	}
+	if h.goroutineID {
+		root.property(kGoroutineID, FieldInteger)
+	}
+
+	if h.source {
+		if h.sourceFormat == SourceGroup {
+			root.property(slog.SourceKey+".function", FieldString)
+			root.property(slog.SourceKey+".file", FieldString)
+			root.property(slog.SourceKey+".line", FieldInteger)
+		} else {
+			root.property(slog.SourceKey, FieldString)
+		}
+	}
+
+	for _, le := range h.enrichers {
+		paths := make([]string, 0, len(le.fields))
+		for path := range le.fields {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+
+		for _, path := range paths {
+			root.property(path, le.fields[path])
+		}
+	}
+
+	static := make(logRecord)
+	static.append(h.buildInfo)
+	value := static
+	for _, ga := range h.gattr {
+		if ga.group == "" {
+			for _, a := range ga.attrs {
+				h.appendUserAttr(static, value, a)
+			}
+		} else {
+			group := make(logRecord)
+			value[ga.group] = group
+			value = group
+		}
+	}
+	root.describe("", static)
+
+	if h.schema != nil {
+		paths := make([]string, 0, len(h.schema.Types))
+		for path := range h.schema.Types {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+
+		for _, path := range paths {
+			root.property(path, h.schema.Types[path])
+		}
+		for _, path := range h.schema.Required {
+			root.require(path)
+		}
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+type jsonSchemaObject map[string]any
+
+func newJSONSchemaObject() jsonSchemaObject {
+	return jsonSchemaObject{
+		"type":                 FieldObject,
+		"properties":           make(map[string]jsonSchemaObject),
+		"additionalProperties": true,
+	}
+}
+
+// property returns the schema of the property at the dot separated path, creating it and any
+// objects containing it.
+func (s jsonSchemaObject) property(path string, fieldType FieldType) jsonSchemaObject {
+	key, rest, nested := strings.Cut(path, ".")
+
+	properties, ok := s["properties"].(map[string]jsonSchemaObject)
+	if !ok {
+		s["type"] = FieldObject
+		s["additionalProperties"] = true
+		properties = make(map[string]jsonSchemaObject)
+		s["properties"] = properties
+	}
+
+	if !nested {
+		prop, ok := properties[key]
+		if !ok {
+			prop = jsonSchemaObject{}
+			properties[key] = prop
+		}
+		if fieldType == FieldObject {
+			if _, ok := prop["properties"]; !ok {
+				clear(prop)
+				for k, v := range newJSONSchemaObject() {
+					prop[k] = v
+				}
+			}
+		} else {
+			clear(prop)
+			prop["type"] = fieldType
+		}
+		return prop
+	}
+
+	return s.property(key, FieldObject).property(rest, fieldType)
+}
+
+// require marks the property at the dot separated path as required, along with the objects
+// containing it.
+func (s jsonSchemaObject) require(path string) {
+	key, rest, nested := strings.Cut(path, ".")
+
+	var prop jsonSchemaObject
+	if nested {
+		prop = s.property(key, FieldObject)
+	} else if prop = s.properties()[key]; prop == nil {
+		s.properties()[key] = jsonSchemaObject{}
+	}
+
+	required, _ := s["required"].([]string)
+	if !slices.Contains(required, key) {
+		required = append(required, key)
+		slices.Sort(required)
+		s["required"] = required
+	}
+
+	if nested {
+		prop.require(rest)
+	}
+}
+
+func (s jsonSchemaObject) properties() map[string]jsonSchemaObject {
+	properties, _ := s["properties"].(map[string]jsonSchemaObject)
+	return properties
+}
+
+// describe adds the fields of the record to the schema, at the given path prefix.
+func (s jsonSchemaObject) describe(prefix string, record logRecord) {
+	for key, value := range record {
+		if group, ok := value.(logRecord); ok {
+			s.property(prefix+key, FieldObject)
+			s.describe(prefix+key+".", group)
+			continue
+		}
+		if fieldType := fieldTypeOf(value); fieldType != "null" {
+			s.property(prefix+key, fieldType)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_JSONSchema(t *testing.T) {
+	schema := &sloglambda.Schema{
+		Required: []string{"order.id"},
+		Types: map[string]sloglambda.FieldType{
+			"order.id":    sloglambda.FieldString,
+			"order.total": sloglambda.FieldNumber,
+		},
+	}
+
+	handler := sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithJSON(), sloglambda.WithSchema(schema), sloglambda.WithType("audit.log"))
+	derived := handler.WithAttrs([]slog.Attr{slog.String("service", "orders")}).WithGroup("request").WithAttrs([]slog.Attr{slog.Int("attempt", 1)})
+
+	document, err := derived.(*sloglambda.Handler).JSONSchema()
+	require.NoError(t, err)
+
+	var out struct {
+		Schema     string   `json:"$schema"`
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Type       string         `json:"type"`
+			Default    string         `json:"default"`
+			Required   []string       `json:"required"`
+			Properties map[string]any `json:"properties"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(document, &out))
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", out.Schema)
+	assert.Equal(t, []string{"level", "msg", "order", "type"}, out.Required)
+
+	assert.Equal(t, "audit.log", out.Properties["type"].Default)
+	assert.Equal(t, "string", out.Properties["service"].Type)
+	assert.Equal(t, "object", out.Properties["request"].Type)
+	assert.Equal(t, map[string]any{"type": "integer"}, out.Properties["request"].Properties["attempt"])
+	assert.Equal(t, map[string]any{"type": "string"}, out.Properties["record"].Properties["functionName"])
+	assert.Equal(t, []string{"id"}, out.Properties["order"].Required)
+	assert.Equal(t, map[string]any{"type": "number"}, out.Properties["order"].Properties["total"])
+	assert.Contains(t, out.Properties, "time")
+
+	t.Run("with insights fields", func(t *testing.T) {
+		document, err := sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithInsightsFields(), sloglambda.WithoutTime()).JSONSchema()
+		require.NoError(t, err)
+
+		out.Properties = nil
+		require.NoError(t, json.Unmarshal(document, &out))
+		assert.Contains(t, out.Properties, "@message")
+		assert.Contains(t, out.Properties, "requestId")
+		assert.NotContains(t, out.Properties, "@timestamp")
+	})
+}
+
+func TestHandler_JSONSchemaDescribesRecords(t *testing.T) {
+	t.Setenv("SCHEMA_FEATURE_FLAG", "on")
+
+	var buf bytes.Buffer
+	handler := sloglambda.NewHandler(&buf,
+		sloglambda.WithJSON(),
+		sloglambda.WithType("app.log"),
+		sloglambda.WithSequence(),
+		sloglambda.WithElapsed(),
+		sloglambda.WithPhase(),
+		sloglambda.WithLevelValue(),
+		sloglambda.WithContextError(),
+		sloglambda.WithSnapStart(),
+		sloglambda.WithCallerAccount(),
+		sloglambda.WithTraceContext(),
+		sloglambda.WithMemoryStats(),
+		sloglambda.WithGoroutineCount(),
+		sloglambda.WithGoroutineID(),
+		sloglambda.WithSource(),
+		sloglambda.WithErrorFingerprint(),
+		sloglambda.WithEnvSnapshot("SCHEMA_FEATURE_FLAG"),
+		sloglambda.WithTenantExtractor(func(context.Context) string { return "tenant-1" }),
+	)
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID:       "schema-1",
+		InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:test-function:live",
+	})
+	ctx = sloglambda.ContextWithCallerAccount(ctx, "210987654321")
+	ctx, err := sloglambda.ContextWithTraceContext(ctx, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "")
+	require.NoError(t, err)
+	ctx, err = sloglambda.ContextWithBaggage(ctx, "team=orders")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	logger := slog.New(handler)
+	logger.InfoContext(ctx, "info")
+	logger.Log(ctx, slog.LevelInfo+2, "custom level")
+	logger.ErrorContext(ctx, "failed", "err", errors.New("boom"))
+
+	document, err := handler.JSONSchema()
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(document, &schema))
+
+	var undescribed []string
+	var check func(path string, record map[string]any, schema map[string]any)
+	check = func(path string, record map[string]any, schema map[string]any) {
+		properties, _ := schema["properties"].(map[string]any)
+		for key, value := range record {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				undescribed = append(undescribed, path+key)
+				continue
+			}
+			if group, ok := value.(map[string]any); ok && len(property["properties"].(map[string]any)) > 0 {
+				check(path+key+".", group, property)
+			}
+		}
+	}
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		delete(record, "err")
+		check("", record, schema)
+	}
+
+	assert.Empty(t, undescribed)
+	for _, key := range []string{"ctxErr", "levelValue", "snapstartRestore", "restoreLatencyMs", "errorFingerprint", "env", "callerAccount", "claimedCallerAccount"} {
+		assert.Contains(t, schema["properties"], key)
+	}
+	assert.Contains(t, schema["properties"].(map[string]any)["record"].(map[string]any)["properties"], "alias")
+}
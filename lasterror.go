@@ -0,0 +1,103 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LoggedError describes a record logged at ERROR or above.
+type LoggedError struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs holds the attributes selected with WithLastError, in the order they were added.
+	Attrs []slog.Attr
+}
+
+// lastErrors holds the most recent LoggedError of each invocation, keyed by request ID.
+type lastErrors struct {
+	mu     sync.Mutex
+	errors map[string]LoggedError
+	order  []string
+}
+
+var recentErrors = &lastErrors{errors: make(map[string]LoggedError)}
+
+func (l *lastErrors) set(requestID string, err LoggedError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.errors[requestID]; !ok {
+		if len(l.order) >= maxTrackedInvocations {
+			delete(l.errors, l.order[0])
+			l.order = slices.Delete(l.order, 0, 1)
+		}
+		l.order = append(l.order, requestID)
+	}
+	l.errors[requestID] = err
+}
+
+func (l *lastErrors) get(requestID string) (LoggedError, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err, ok := l.errors[requestID]
+	return err, ok
+}
+
+// LastError returns the most recent record logged at ERROR or above for the invocation associated
+// with ctx, by any Handler configured with WithLastError.
+//
+// It lets middleware or the function's response builder report exactly what was last logged as
+// an error. Errors are retained for the most recent invocations only.
+func LastError(ctx context.Context) (LoggedError, bool) {
+	return recentErrors.get(requestIDFromContext(ctx))
+}
+
+// WithLastError configures the Handler to record the most recent record logged at ERROR or above
+// for each invocation, retrieved with LastError. Top level attributes with the given keys are
+// included in the LoggedError.
+func WithLastError(keys ...string) Option {
+	return func(h *Handler) {
+		h.lastError = true
+		h.lastErrorKeys = keys
+	}
+}
+
+// captureError records the record as the last error of the invocation associated with ctx.
+func (h *Handler) captureError(ctx context.Context, record slog.Record) {
+	err := LoggedError{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+	}
+
+	selected := func(a slog.Attr) bool {
+		if slices.Contains(h.lastErrorKeys, a.Key) {
+			a.Value = a.Value.Resolve()
+			err.Attrs = append(err.Attrs, a)
+		}
+		return true
+	}
+
+	if len(h.lastErrorKeys) > 0 {
+		topLevel := true
+		for _, ga := range h.gattr {
+			if ga.group != "" {
+				topLevel = false
+				break
+			}
+			for _, a := range ga.attrs {
+				selected(a)
+			}
+		}
+		if topLevel {
+			record.Attrs(selected)
+		}
+	}
+
+	recentErrors.set(requestIDFromContext(ctx), err)
+}
@@ -0,0 +1,37 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastError(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "last-error-1"})
+	other := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "last-error-2"})
+
+	logger := slog.New(sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithLastError("code", "orderId")))
+
+	_, ok := sloglambda.LastError(ctx)
+	assert.False(t, ok)
+
+	logger.ErrorContext(ctx, "first failure", "code", 1)
+	logger.With("orderId", "o-1").ErrorContext(ctx, "payment declined", "code", 402, "ignored", true)
+	logger.WarnContext(ctx, "not an error")
+
+	err, ok := sloglambda.LastError(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "payment declined", err.Message)
+	assert.Equal(t, slog.LevelError, err.Level)
+	assert.False(t, err.Time.IsZero())
+	assert.Equal(t, []slog.Attr{slog.String("orderId", "o-1"), slog.Int("code", 402)}, err.Attrs)
+
+	_, ok = sloglambda.LastError(other)
+	assert.False(t, ok)
+}
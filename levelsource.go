@@ -0,0 +1,80 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"os"
+)
+
+// LevelSource is a place the level of a Handler can be read from, such as an environment variable.
+type LevelSource struct {
+	// Name identifies the source in the record written by WithDiagnostics.
+	Name string
+	// Resolve returns the level, or false if the source does not provide one.
+	Resolve func() (slog.Level, bool)
+}
+
+// EnvLevelSource returns a LevelSource reading the level from the environment variable key, in the
+// format accepted by ParseLevel. It provides no level when the variable is unset or invalid.
+func EnvLevelSource(key string) LevelSource {
+	return LevelSource{
+		Name: key,
+		Resolve: func() (slog.Level, bool) {
+			level, err := ParseLevel(os.Getenv(key))
+			return level, err == nil
+		},
+	}
+}
+
+// LambdaLevelSource returns a LevelSource reading the level from AWS_LAMBDA_LOG_LEVEL, set by the
+// Lambda advanced logging controls.
+func LambdaLevelSource() LevelSource {
+	return EnvLevelSource(lambdaEnvLogLevel)
+}
+
+// DefaultLevelSource returns a LevelSource that always provides level, for the end of a list of
+// sources.
+func DefaultLevelSource(level slog.Level) LevelSource {
+	return LevelSource{
+		Name: settingDefault,
+		Resolve: func() (slog.Level, bool) {
+			return level, true
+		},
+	}
+}
+
+// defaultLevelSources are the sources NewHandler reads the level from.
+var defaultLevelSources = []LevelSource{
+	LambdaLevelSource(),
+	DefaultLevelSource(slog.LevelInfo),
+}
+
+// WithLevelSources configures the Handler to use the level of the first source that provides one,
+// replacing the default of AWS_LAMBDA_LOG_LEVEL followed by INFO:
+//
+//	sloglambda.WithLevelSources(
+//		sloglambda.EnvLevelSource("MY_SERVICE_LOG_LEVEL"),
+//		sloglambda.LambdaLevelSource(),
+//		sloglambda.DefaultLevelSource(slog.LevelWarn),
+//	)
+//
+// A level given with WithLevel or WithAdaptiveLevel takes precedence over every source, regardless
+// of the order of the options. When no source provides a level, the level is left unchanged. The
+// name of the source that provided the level is reported by WithDiagnostics.
+func WithLevelSources(sources ...LevelSource) Option {
+	return func(h *Handler) {
+		if h.levelSource == settingOption {
+			return
+		}
+		for _, source := range sources {
+			if source.Resolve == nil {
+				h.invalidOption("WithLevelSources: source %q has no Resolve function", source.Name)
+				continue
+			}
+			if level, ok := source.Resolve(); ok {
+				h.level = level
+				h.levelSource = source.Name
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLevelSources(t *testing.T) {
+	sources := sloglambda.WithLevelSources(
+		sloglambda.EnvLevelSource("SERVICE_LOG_LEVEL"),
+		sloglambda.LambdaLevelSource(),
+		sloglambda.DefaultLevelSource(slog.LevelWarn),
+	)
+
+	t.Run("first source wins", func(t *testing.T) {
+		t.Setenv("SERVICE_LOG_LEVEL", "debug")
+		t.Setenv("AWS_LAMBDA_LOG_LEVEL", "error")
+
+		assert.Equal(t, slog.LevelDebug, sloglambda.NewHandler(nil, sources).Level())
+	})
+
+	t.Run("falls through unset and invalid sources", func(t *testing.T) {
+		t.Setenv("SERVICE_LOG_LEVEL", "loud")
+		t.Setenv("AWS_LAMBDA_LOG_LEVEL", "error")
+
+		assert.Equal(t, slog.LevelError, sloglambda.NewHandler(nil, sources).Level())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, slog.LevelWarn, sloglambda.NewHandler(nil, sources).Level())
+	})
+
+	t.Run("explicit level wins", func(t *testing.T) {
+		t.Setenv("SERVICE_LOG_LEVEL", "debug")
+
+		assert.Equal(t, slog.LevelError, sloglambda.NewHandler(nil, sloglambda.WithLevel(slog.LevelError), sources).Level())
+		assert.Equal(t, slog.LevelError, sloglambda.NewHandler(nil, sources, sloglambda.WithLevel(slog.LevelError)).Level())
+	})
+
+	t.Run("reported by diagnostics", func(t *testing.T) {
+		t.Setenv("SERVICE_LOG_LEVEL", "debug")
+
+		var buf bytes.Buffer
+		sloglambda.NewHandler(&buf, sources, sloglambda.WithDiagnostics())
+		assert.Contains(t, buf.String(), `"levelSource":"SERVICE_LOG_LEVEL"`)
+	})
+
+	t.Run("missing resolve function", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithLevelSources(sloglambda.LevelSource{Name: "broken"}))
+		require.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
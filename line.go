@@ -0,0 +1,86 @@
+package sloglambda
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// DefaultMaxLineSize is the default maximum size of an encoded record, including the trailing
+// newline. It matches the maximum size of a CloudWatch Logs event.
+const DefaultMaxLineSize = 256 << 10
+
+var kTruncated = "truncated"
+
+// ErrRecordTooLarge is reported to the error handler when a record exceeds the maximum line size.
+var ErrRecordTooLarge = errors.New("record too large")
+
+// WithMaxLineSize configures the maximum size in bytes of an encoded record, including the trailing
+// newline. A size of zero disables the limit. The default is DefaultMaxLineSize.
+//
+// Each record is written with a single call to Write. A record exceeding the limit is replaced by
+// one containing only its level, message, time, type, and Lambda context, with a "truncated"
+// field holding the size of the original record, and the message shortened if needed. The
+// replacement is reported to the error handler (see WithErrorHandler).
+func WithMaxLineSize(size int) Option {
+	return func(h *Handler) {
+		if size < 0 {
+			h.invalidOption("WithMaxLineSize: negative size %d", size)
+			return
+		}
+		h.maxLineSize = size
+	}
+}
+
+// truncate replaces the encoded record in buf with a smaller record that fits in the maximum line
+// size.
+func (h *Handler) truncate(buf *bytes.Buffer, record logRecord) error {
+	size := buf.Len()
+
+	keep := make(logRecord, 6)
+	for _, key := range []string{slog.LevelKey, h.messageKey(), h.timeKey(), kLambdaRecord, kLambdaLogType, kLambdaRequestId} {
+		if value, ok := record[key]; ok {
+			keep[key] = value
+		}
+	}
+	keep[kTruncated] = size
+
+	message, _ := keep[h.messageKey()].(string)
+	for {
+		buf.Reset()
+		if err := h.encode(buf, keep); err != nil {
+			return err
+		}
+
+		excess := buf.Len() - h.maxLineSize
+		if excess <= 0 {
+			return nil
+		}
+		if message == "" {
+			buf.Reset()
+			return fmt.Errorf("%w: %d bytes exceeds the maximum line size of %d bytes", ErrRecordTooLarge, size, h.maxLineSize)
+		}
+
+		message = truncateString(message, len(message)-excess-len("…"))
+		keep[h.messageKey()] = message + "…"
+		if message == "" {
+			keep[h.messageKey()] = ""
+		}
+	}
+}
+
+// truncateString shortens s to at most n bytes without splitting a UTF-8 sequence.
+func truncateString(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
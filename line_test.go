@@ -0,0 +1,54 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxLineSize(t *testing.T) {
+	var reported []error
+
+	out := new(lineRecorder)
+	logger := slog.New(sloglambda.NewHandler(out, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithMaxLineSize(200), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+		reported = append(reported, err)
+	})))
+
+	logger.Info("small")
+	logger.Info("large", "payload", strings.Repeat("x", 500))
+	logger.Info(strings.Repeat("ü", 200))
+
+	require.Len(t, out.writes, 3)
+	for _, w := range out.writes {
+		assert.LessOrEqual(t, len(w), 200)
+		assert.True(t, strings.HasSuffix(w, "}\n"))
+		assert.True(t, json.Valid([]byte(w)))
+	}
+
+	var large map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out.writes[1]), &large))
+	assert.Equal(t, "large", large["msg"])
+	assert.NotContains(t, large, "payload")
+	assert.Greater(t, large["truncated"], float64(500))
+
+	var long map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out.writes[2]), &long))
+	assert.True(t, strings.HasSuffix(long["msg"].(string), "ü…"))
+
+	require.Len(t, reported, 2)
+	assert.ErrorIs(t, reported[0], sloglambda.ErrRecordTooLarge)
+
+	t.Run("disabled", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithMaxLineSize(0))).Info(strings.Repeat("x", sloglambda.DefaultMaxLineSize))
+
+		assert.Greater(t, buffer.Len(), sloglambda.DefaultMaxLineSize)
+	})
+}
@@ -0,0 +1,170 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogWriter is an io.Writer that splits its input into lines and emits each line as a record
+// through a slog.Handler.
+//
+// It bridges libraries that log with the standard library log package, or that write to a
+// provided io.Writer, into the structured output of the handler.
+type LogWriter struct {
+	ctx         context.Context
+	handler     slog.Handler
+	level       slog.Level
+	detectLevel bool
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// LogWriterOption configures a LogWriter.
+type LogWriterOption func(*LogWriter)
+
+// DetectLevel configures the LogWriter to infer the level of each line from a leading prefix such
+// as "ERROR:", "[WARN]", or "debug ". The prefix is removed from the message. Lines without a
+// recognized prefix use the LogWriter's default level.
+func DetectLevel() LogWriterOption {
+	return func(w *LogWriter) {
+		w.detectLevel = true
+	}
+}
+
+// LogWriterContext configures the LogWriter to emit records with ctx, so records written during an
+// invocation carry its Lambda context and count toward its budget and statistics. The default is
+// context.Background.
+func LogWriterContext(ctx context.Context) LogWriterOption {
+	return func(w *LogWriter) {
+		w.ctx = ctx
+	}
+}
+
+// NewLogWriter creates a LogWriter that emits lines through h at the given level.
+func NewLogWriter(h slog.Handler, level slog.Level, options ...LogWriterOption) *LogWriter {
+	w := &LogWriter{
+		ctx:     context.Background(),
+		handler: h,
+		level:   level,
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// NewLogLogger creates a standard library *log.Logger that emits each line it logs through h.
+func NewLogLogger(h slog.Handler, level slog.Level, options ...LogWriterOption) *log.Logger {
+	return log.New(NewLogWriter(h, level, options...), "", 0)
+}
+
+// Write implements io.Writer. Incomplete lines are buffered until a newline is written or the
+// LogWriter is flushed.
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush emits any buffered incomplete line.
+func (w *LogWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+	return nil
+}
+
+// Close flushes the LogWriter.
+func (w *LogWriter) Close() error {
+	return w.Flush()
+}
+
+func (w *LogWriter) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	level := w.level
+	if w.detectLevel {
+		if l, rest, ok := detectLinePrefixLevel(line); ok {
+			level, line = l, rest
+		}
+	}
+
+	if !w.handler.Enabled(w.ctx, level) {
+		return
+	}
+
+	_ = w.handler.Handle(w.ctx, slog.NewRecord(time.Now(), level, line, 0))
+}
+
+var _ io.WriteCloser = (*LogWriter)(nil)
+
+var linePrefixLevels = []struct {
+	prefix string
+	level  slog.Level
+}{
+	{"TRACE", slog.LevelDebug - traceLevelDebugOffset},
+	{"DEBUG", slog.LevelDebug},
+	{"INFO", slog.LevelInfo},
+	{"WARNING", slog.LevelWarn},
+	{"WARN", slog.LevelWarn},
+	{"ERROR", slog.LevelError},
+	{"FATAL", slog.LevelError + fatalLevelErrorOffset},
+}
+
+// detectLinePrefixLevel parses a level prefix such as "ERROR:", "[ERROR]", or "ERROR " from the
+// start of the line, returning the level and the remainder of the line.
+func detectLinePrefixLevel(line string) (slog.Level, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	bracketed := strings.HasPrefix(trimmed, "[")
+	if bracketed {
+		trimmed = trimmed[1:]
+	}
+
+	for _, p := range linePrefixLevels {
+		if len(trimmed) < len(p.prefix) || !strings.EqualFold(trimmed[:len(p.prefix)], p.prefix) {
+			continue
+		}
+
+		rest := trimmed[len(p.prefix):]
+		switch {
+		case bracketed && strings.HasPrefix(rest, "]"):
+			rest = rest[1:]
+		case !bracketed && strings.HasPrefix(rest, ":"):
+			rest = rest[1:]
+		case !bracketed && strings.HasPrefix(rest, " "):
+		default:
+			continue
+		}
+
+		return p.level, strings.TrimLeft(rest, " "), true
+	}
+
+	return 0, line, false
+}
@@ -0,0 +1,77 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogWriter(t *testing.T) {
+	t.Run("emits a record per line", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		w := sloglambda.NewLogWriter(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), slog.LevelInfo)
+
+		fmt.Fprint(w, "first line\nsecond ")
+		fmt.Fprint(w, "line\npartial")
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"msg":"first line"`)
+		assert.Contains(t, lines[1], `"msg":"second line"`)
+
+		require.NoError(t, w.Close())
+		assert.Contains(t, buffer.String(), `"msg":"partial"`)
+	})
+
+	t.Run("detects the level", func(t *testing.T) {
+		cases := map[string]string{
+			"ERROR: failed":   `"level":"ERROR","msg":"failed"`,
+			"[warn] careful":  `"level":"WARN","msg":"careful"`,
+			"WARNING: slow":   `"level":"WARN","msg":"slow"`,
+			"debug details":   ``,
+			"informative msg": `"level":"INFO","msg":"informative msg"`,
+		}
+
+		for line, expected := range cases {
+			t.Run(line, func(t *testing.T) {
+				buffer := new(bytes.Buffer)
+				w := sloglambda.NewLogWriter(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), slog.LevelInfo, sloglambda.DetectLevel())
+
+				fmt.Fprintln(w, line)
+
+				if expected == "" {
+					assert.Empty(t, buffer.String())
+				} else {
+					assert.Contains(t, buffer.String(), expected)
+				}
+			})
+		}
+	})
+}
+
+func TestNewLogLogger(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := sloglambda.NewLogLogger(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), slog.LevelWarn)
+
+	logger.Printf("legacy %s", "output")
+
+	assert.Contains(t, buffer.String(), `"level":"WARN","msg":"legacy output"`)
+}
+
+func TestLogWriterContext(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "writer-123"})
+	w := sloglambda.NewLogWriter(sloglambda.NewHandler(buffer, sloglambda.WithJSON()), slog.LevelInfo, sloglambda.LogWriterContext(ctx))
+
+	fmt.Fprintln(w, "from a library")
+
+	assert.Contains(t, buffer.String(), `"requestId":"writer-123"`)
+}
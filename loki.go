@@ -0,0 +1,169 @@
+package sloglambda
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LokiWriter is an io.Writer that pushes the records written by a Handler to the push API of a
+// Grafana Loki endpoint, for teams whose observability stack is Loki rather than CloudWatch.
+//
+// The Handler must write JSON (see WithJSON). Records are buffered and pushed in batches, when the
+// batch is full, when the Handler ends an invocation (see Handler.EndInvocation), and on Flush or
+// Close. Each record is pushed as a JSON line to the stream identified by its "function",
+// "version", and "type" labels, taken from the record, along with any static labels.
+type LokiWriter struct {
+	url    string
+	labels map[string]string
+	batch  *httpBatch
+}
+
+// NewLokiWriter creates a LokiWriter that pushes records to the Loki push API at url, for example
+// "http://loki:3100/loki/api/v1/push".
+func NewLokiWriter(url string) *LokiWriter {
+	w := &LokiWriter{url: url}
+	w.batch = newHTTPBatch("loki", w.push)
+	return w
+}
+
+// WithClient configures the http.Client used to push records.
+func (w *LokiWriter) WithClient(client *http.Client) *LokiWriter {
+	w.batch.client = client
+	return w
+}
+
+// WithBatchSize configures the number of records buffered before they are pushed.
+func (w *LokiWriter) WithBatchSize(size int) *LokiWriter {
+	w.batch.batchSize = size
+	return w
+}
+
+// WithTimeout configures how long the LokiWriter waits for a batch to be pushed.
+func (w *LokiWriter) WithTimeout(timeout time.Duration) *LokiWriter {
+	w.batch.timeout = timeout
+	return w
+}
+
+// WithGzip configures the LokiWriter to gzip compress batches whose payload is at least minSize
+// bytes, trading CPU time for fewer bytes sent. A minSize of zero compresses every batch.
+func (w *LokiWriter) WithGzip(minSize int) *LokiWriter {
+	w.batch.withGzip(minSize)
+	return w
+}
+
+// WithLabels configures static labels added to every stream.
+func (w *LokiWriter) WithLabels(labels map[string]string) *LokiWriter {
+	w.labels = labels
+	return w
+}
+
+// Write implements io.Writer.
+func (w *LokiWriter) Write(p []byte) (int, error) {
+	return w.batch.write(p)
+}
+
+// Flush pushes the buffered records.
+func (w *LokiWriter) Flush() error {
+	return w.batch.Flush()
+}
+
+// Close pushes the buffered records.
+func (w *LokiWriter) Close() error {
+	return w.Flush()
+}
+
+var _ io.WriteCloser = (*LokiWriter)(nil)
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (w *LokiWriter) push(ctx context.Context, records []httpRecord) error {
+	streams := make(map[string]*lokiStream)
+	var order []string
+
+	for _, record := range records {
+		fields := record.fields
+
+		labels := w.streamLabels(fields)
+		key := lokiStreamKey(labels)
+
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(recordTime(fields).UnixNano(), 10),
+			string(record.raw),
+		})
+	}
+
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range order {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, _, err := w.batch.newRequest(ctx, w.url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = w.batch.do("loki", req)
+	return err
+}
+
+// streamLabels returns the labels of the stream the decoded record belongs to.
+func (w *LokiWriter) streamLabels(fields map[string]any) map[string]string {
+	labels := make(map[string]string, len(w.labels)+3)
+	for k, v := range w.labels {
+		labels[k] = v
+	}
+
+	if lambda, ok := fields[kLambdaRecord].(map[string]any); ok {
+		if name, ok := lambda[kLambdaFunctionName].(string); ok {
+			labels["function"] = name
+		}
+		if version, ok := lambda[kLambdaFunctionVersion].(string); ok {
+			labels["version"] = version
+		}
+	}
+	if logType, ok := fields[kLambdaLogType].(string); ok {
+		labels["type"] = logType
+	}
+
+	return labels
+}
+
+func lokiStreamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package sloglambda_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLokiWriter(t *testing.T) {
+	type push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+
+	var pushes []push
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var p push
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &p))
+		pushes = append(pushes, p)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	w := sloglambda.NewLokiWriter(server.URL).WithLabels(map[string]string{"env": "test"}).WithBatchSize(10)
+	handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+	logger := slog.New(handler)
+
+	logger.Info("one")
+	logger.Info("two", sloglambda.Type("audit.log"))
+	assert.Empty(t, pushes)
+
+	require.NoError(t, handler.EndInvocation(context.Background()))
+	require.Len(t, pushes, 1)
+	require.Len(t, pushes[0].Streams, 2)
+
+	assert.Equal(t, map[string]string{"env": "test", "function": "test-function", "version": "$LATEST", "type": "app.log"}, pushes[0].Streams[0].Stream)
+	assert.Equal(t, "audit.log", pushes[0].Streams[1].Stream["type"])
+	assert.Contains(t, pushes[0].Streams[0].Values[0][1], `"msg":"one"`)
+	assert.Regexp(t, `^[0-9]{19}$`, pushes[0].Streams[0].Values[0][0])
+
+	t.Run("reports failed pushes", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		}))
+		defer failing.Close()
+
+		w := sloglambda.NewLokiWriter(failing.URL)
+		_, err := w.Write([]byte(`{"msg":"x"}` + "\n"))
+		require.NoError(t, err)
+
+		assert.EqualError(t, w.Flush(), "loki: unexpected response 429 Too Many Requests: rate limited")
+	})
+}
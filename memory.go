@@ -0,0 +1,52 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var kMemoryStats = "memory"
+
+// memoryStatsInterval is the minimum time between reads of the runtime memory statistics.
+// runtime.ReadMemStats stops the world, so reading it for every record is too expensive.
+const memoryStatsInterval = time.Second
+
+type memoryStatsCache struct {
+	mu      sync.Mutex
+	sampled time.Time
+	heap    uint64
+	sys     uint64
+}
+
+var memoryStats = new(memoryStatsCache)
+
+func (c *memoryStatsCache) attr() slog.Attr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := time.Now(); now.Sub(c.sampled) >= memoryStatsInterval {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+
+		c.sampled = now
+		c.heap = stats.HeapInuse
+		c.sys = stats.Sys
+	}
+
+	return slog.Group(kMemoryStats,
+		slog.Uint64("heapInUse", c.heap),
+		slog.Uint64("sys", c.sys),
+	)
+}
+
+// WithMemoryStats configures the Handler to include a "memory" group with the heap in use and the
+// total memory obtained from the OS, in bytes.
+//
+// The statistics are sampled at most once per second and shared by all Handlers in the process.
+func WithMemoryStats() Option {
+	return func(h *Handler) {
+		h.memoryStats = true
+	}
+}
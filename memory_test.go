@@ -0,0 +1,20 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMemoryStats(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithMemoryStats()))
+
+	logger.Info(t.Name())
+
+	assert.Contains(t, buffer.String(), `memory.heapInUse=`)
+	assert.Contains(t, buffer.String(), `memory.sys=`)
+}
@@ -0,0 +1,227 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+)
+
+// MsgpackEncoder writes records as MessagePack maps, with the same structure as their JSON encoding.
+// The binary records are smaller and cheaper to parse than JSON, for sinks such as Firehose, Kafka,
+// or an extension socket, rather than CloudWatch.
+//
+// Records are not followed by a terminator, so each one should be written to a sink that frames
+// records itself; a stream of concatenated records can also be decoded one map at a time.
+type MsgpackEncoder struct{}
+
+// Encode implements Encoder.
+func (MsgpackEncoder) Encode(buf *bytes.Buffer, fields Fields) error {
+	b, err := appendMsgpack(buf.AvailableBuffer(), fields)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// Binary implements BinaryEncoder.
+func (MsgpackEncoder) Binary() {}
+
+var _ BinaryEncoder = MsgpackEncoder{}
+
+// WithMsgpack configures the Handler to write records as MessagePack (see MsgpackEncoder).
+func WithMsgpack() Option {
+	return WithEncoder(MsgpackEncoder{})
+}
+
+// appendMsgpack appends the MessagePack encoding of v to b.
+//
+// Records are encoded with the same structure as their JSON encoding: times are formatted as
+// RFC 3339 strings, map keys are sorted, and values without a MessagePack representation are
+// encoded through their JSON encoding.
+func appendMsgpack(b []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case int:
+		return appendMsgpackInt(b, int64(v)), nil
+	case int8:
+		return appendMsgpackInt(b, int64(v)), nil
+	case int16:
+		return appendMsgpackInt(b, int64(v)), nil
+	case int32:
+		return appendMsgpackInt(b, int64(v)), nil
+	case int64:
+		return appendMsgpackInt(b, v), nil
+	case uint:
+		return appendMsgpackUint(b, uint64(v)), nil
+	case uint8:
+		return appendMsgpackUint(b, uint64(v)), nil
+	case uint16:
+		return appendMsgpackUint(b, uint64(v)), nil
+	case uint32:
+		return appendMsgpackUint(b, uint64(v)), nil
+	case uint64:
+		return appendMsgpackUint(b, v), nil
+	case float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(v)), nil
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case string:
+		return appendMsgpackString(b, v), nil
+	case []byte:
+		return appendMsgpackBinary(b, v), nil
+	case time.Time:
+		return appendMsgpackString(b, v.Format(time.RFC3339Nano)), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return appendMsgpack(b, f)
+	case logRecord:
+		return appendMsgpackMap(b, v)
+	case map[string]any:
+		return appendMsgpackMap(b, v)
+	case []any:
+		b = appendMsgpackHeader(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, e := range v {
+			var err error
+			if b, err = appendMsgpack(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case json.RawMessage:
+		return appendMsgpackJSON(b, v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendMsgpackJSON(b, data)
+	}
+}
+
+// appendMsgpackJSON appends the MessagePack encoding of a JSON document to b.
+func appendMsgpackJSON(b []byte, data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return appendMsgpack(b, v)
+}
+
+func appendMsgpackMap[M ~map[string]any](b []byte, m M) ([]byte, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	b = appendMsgpackHeader(b, len(m), 0x80, 0xde, 0xdf)
+	for _, k := range keys {
+		b = appendMsgpackString(b, k)
+
+		var err error
+		if b, err = appendMsgpack(b, m[k]); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendMsgpackHeader appends the header of an array or map with n elements, using the fix format
+// for fewer than 16 elements.
+func appendMsgpackHeader(b []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgpackUint(b, uint64(i))
+	case i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+func appendMsgpackUint(b []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(b, byte(u))
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackBinary(b []byte, p []byte) []byte {
+	switch n := len(p); {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, p...)
+}
+
+// appendMsgpackEventTime appends t as a Fluentd EventTime extension (type 0), which carries
+// nanosecond precision.
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
@@ -0,0 +1,96 @@
+package sloglambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_appendMsgpack(t *testing.T) {
+	cases := map[string]struct {
+		value    any
+		expected []byte
+	}{
+		"nil":              {nil, []byte{0xc0}},
+		"true":             {true, []byte{0xc3}},
+		"positive fixint":  {int64(7), []byte{0x07}},
+		"negative fixint":  {-3, []byte{0xfd}},
+		"int8":             {int8(-100), []byte{0xd0, 0x9c}},
+		"int16":            {int64(-1000), []byte{0xd1, 0xfc, 0x18}},
+		"uint8":            {uint64(200), []byte{0xcc, 0xc8}},
+		"uint16":           {300, []byte{0xcd, 0x01, 0x2c}},
+		"uint64":           {uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		"float64":          {1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		"fixstr":           {"hi", []byte{0xa2, 'h', 'i'}},
+		"binary":           {[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		"json number":      {json.Number("12"), []byte{0x0c}},
+		"array":            {[]any{"a", false}, []byte{0x92, 0xa1, 'a', 0xc2}},
+		"map sorted keys":  {logRecord{"b": 1, "a": logRecord{}}, []byte{0x82, 0xa1, 'a', 0x80, 0xa1, 'b', 0x01}},
+		"raw json":         {json.RawMessage(`{"x":[1]}`), []byte{0x81, 0xa1, 'x', 0x91, 0x01}},
+		"marshaled struct": {struct{ A int }{1}, []byte{0x81, 0xa1, 'A', 0x01}},
+		"time": {
+			time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			append([]byte{0xb4}, "2024-01-02T03:04:05Z"...),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := appendMsgpack(nil, tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, b)
+		})
+	}
+
+	t.Run("str8", func(t *testing.T) {
+		b, err := appendMsgpack(nil, strings.Repeat("x", 40))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0xd9, 40}, b[:2])
+		assert.Len(t, b, 42)
+	})
+
+	t.Run("unsupported value", func(t *testing.T) {
+		_, err := appendMsgpack(nil, func() {})
+		assert.Error(t, err)
+	})
+}
+
+func Test_appendMsgpackEventTime(t *testing.T) {
+	b := appendMsgpackEventTime(nil, time.Unix(1, 2))
+	assert.Equal(t, []byte{0xd7, 0x00, 0, 0, 0, 1, 0, 0, 0, 2}, b)
+}
+
+func TestWithMsgpack(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(NewHandler(buffer, WithMsgpack(), WithoutTime(), WithTerminator("\r\n")))
+
+	logger.Info("Hello, world!", "value", 10)
+	logger.Info("second")
+
+	first, err := appendMsgpack(nil, logRecord{
+		"level":  "INFO",
+		"msg":    "Hello, world!",
+		"record": logRecord{"functionName": "test-function", "version": "$LATEST"},
+		"type":   "app.log",
+		"value":  int64(10),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, byte(10), first[len(first)-1], "the record ends with a 0x0a byte that is not a terminator")
+
+	second, err := appendMsgpack(nil, logRecord{
+		"level":  "INFO",
+		"msg":    "second",
+		"record": logRecord{"functionName": "test-function", "version": "$LATEST"},
+		"type":   "app.log",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, append(first, second...), buffer.Bytes())
+}
@@ -0,0 +1,84 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Once returns a logger that writes each message at most once per invocation, for warnings that
+// would otherwise repeat for every item an invocation processes.
+//
+// Messages are tracked by the request ID of the invocation ctx belongs to, so records logged
+// without a context, as with Once(ctx).Warn(...), are still written once per invocation. When ctx
+// carries no request ID, the request ID of the context a record is logged with is used, and outside
+// of an invocation messages are tracked for the life of the execution environment. Records are
+// written with the logger from ctx (see LoggerFromContext).
+func Once(ctx context.Context) *slog.Logger {
+	requestID := requestIDFromContext(ctx)
+	return slog.New(Filter(LoggerFromContext(ctx).Handler(), func(ctx context.Context, record slog.Record) bool {
+		id := requestID
+		if id == "" {
+			id = requestIDFromContext(ctx)
+		}
+		return onceState.firstInInvocation(id, record.Message)
+	}))
+}
+
+// OncePerEnvironment returns a logger that writes each message at most once for the life of the
+// execution environment, for configuration warnings that apply to every invocation. Records are
+// written with the logger from ctx (see LoggerFromContext).
+func OncePerEnvironment(ctx context.Context) *slog.Logger {
+	return slog.New(Filter(LoggerFromContext(ctx).Handler(), onceState.firstInEnvironment))
+}
+
+var onceState = newOnceMessages()
+
+// onceMessages records the messages written by the Once loggers.
+type onceMessages struct {
+	mu          sync.Mutex
+	environment map[string]struct{}
+	invocations map[string]map[string]struct{}
+	order       []string
+}
+
+func newOnceMessages() *onceMessages {
+	return &onceMessages{
+		environment: make(map[string]struct{}),
+		invocations: make(map[string]map[string]struct{}),
+	}
+}
+
+func (o *onceMessages) firstInEnvironment(_ context.Context, record slog.Record) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return first(o.environment, record.Message)
+}
+
+func (o *onceMessages) firstInInvocation(requestID, msg string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	seen, ok := o.invocations[requestID]
+	if !ok {
+		if len(o.order) >= maxTrackedInvocations {
+			delete(o.invocations, o.order[0])
+			o.order = o.order[1:]
+		}
+		seen = make(map[string]struct{})
+		o.invocations[requestID] = seen
+		o.order = append(o.order, requestID)
+	}
+
+	return first(seen, msg)
+}
+
+// first adds msg to seen, reporting whether it was not already present.
+func first(seen map[string]struct{}, msg string) bool {
+	if _, ok := seen[msg]; ok {
+		return false
+	}
+	seen[msg] = struct{}{}
+	return true
+}
@@ -0,0 +1,56 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(sloglambda.NewHandler(&buf, sloglambda.WithJSON()))
+	base := sloglambda.ContextWithLogger(context.Background(), logger)
+
+	invocation := func(requestID string) context.Context {
+		return lambdacontext.NewContext(base, &lambdacontext.LambdaContext{AwsRequestID: requestID})
+	}
+
+	t.Run("once per invocation", func(t *testing.T) {
+		buf.Reset()
+		first, second := invocation("once-1"), invocation("once-2")
+
+		sloglambda.Once(first).WarnContext(first, "missing config")
+		sloglambda.Once(first).WarnContext(first, "missing config")
+		sloglambda.Once(first).WarnContext(first, "other warning")
+		sloglambda.Once(second).WarnContext(second, "missing config")
+
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte(`"msg":"missing config"`)))
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"msg":"other warning"`)))
+	})
+
+	t.Run("keyed by the invocation of the Once context", func(t *testing.T) {
+		buf.Reset()
+		first, second := invocation("plain-1"), invocation("plain-2")
+
+		sloglambda.Once(first).Warn("missing region")
+		sloglambda.Once(first).Warn("missing region")
+		sloglambda.Once(second).Warn("missing region")
+
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte(`"msg":"missing region"`)))
+	})
+
+	t.Run("once per environment", func(t *testing.T) {
+		buf.Reset()
+		first, second := invocation("env-1"), invocation("env-2")
+
+		sloglambda.OncePerEnvironment(first).WarnContext(first, "deprecated setting")
+		sloglambda.OncePerEnvironment(second).WarnContext(second, "deprecated setting")
+
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"msg":"deprecated setting"`)))
+	})
+}
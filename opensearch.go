@@ -0,0 +1,192 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenSearchIndex is the default index name template of an OpenSearchWriter.
+const DefaultOpenSearchIndex = "logs-{function}-{date}"
+
+// OpenSearchWriter is an io.Writer that writes the records written by a Handler to an
+// Elasticsearch or OpenSearch domain with the _bulk API, so small deployments can skip the
+// Firehose or subscription filter plumbing.
+//
+// The Handler must write JSON (see WithJSON). Records are buffered and written in batches, when
+// the batch is full, when the Handler ends an invocation (see Handler.EndInvocation), and on Flush
+// or Close.
+type OpenSearchWriter struct {
+	endpoint string
+	index    string
+	sign     func(req *http.Request, body []byte) error
+	batch    *httpBatch
+}
+
+// NewOpenSearchWriter creates an OpenSearchWriter that writes records to the domain at endpoint,
+// for example "https://search-logs-abc123.us-east-1.es.amazonaws.com".
+func NewOpenSearchWriter(endpoint string) *OpenSearchWriter {
+	w := &OpenSearchWriter{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		index:    DefaultOpenSearchIndex,
+	}
+	w.batch = newHTTPBatch("opensearch", w.bulk)
+	return w
+}
+
+// WithIndex configures the template of the index each record is written to. The placeholders
+// "{function}", "{version}", and "{type}" are replaced with the record's function name, version,
+// and type, and "{date}" with the date of the record formatted as "2006.01.02".
+func (w *OpenSearchWriter) WithIndex(template string) *OpenSearchWriter {
+	w.index = template
+	return w
+}
+
+// WithSigner configures a function that signs each request before it is sent, for example with
+// AWS Signature Version 4 for an Amazon OpenSearch Service domain. body is the request body as sent,
+// compressed when WithGzip applies, for computing the payload hash.
+func (w *OpenSearchWriter) WithSigner(sign func(req *http.Request, body []byte) error) *OpenSearchWriter {
+	w.sign = sign
+	return w
+}
+
+// WithClient configures the http.Client used to write records.
+func (w *OpenSearchWriter) WithClient(client *http.Client) *OpenSearchWriter {
+	w.batch.client = client
+	return w
+}
+
+// WithBatchSize configures the number of records buffered before they are written.
+func (w *OpenSearchWriter) WithBatchSize(size int) *OpenSearchWriter {
+	w.batch.batchSize = size
+	return w
+}
+
+// WithTimeout configures how long the OpenSearchWriter waits for a batch to be written.
+func (w *OpenSearchWriter) WithTimeout(timeout time.Duration) *OpenSearchWriter {
+	w.batch.timeout = timeout
+	return w
+}
+
+// WithGzip configures the OpenSearchWriter to gzip compress batches whose payload is at least minSize
+// bytes, trading CPU time for fewer bytes sent. A minSize of zero compresses every batch.
+func (w *OpenSearchWriter) WithGzip(minSize int) *OpenSearchWriter {
+	w.batch.withGzip(minSize)
+	return w
+}
+
+// Write implements io.Writer.
+func (w *OpenSearchWriter) Write(p []byte) (int, error) {
+	return w.batch.write(p)
+}
+
+// Flush writes the buffered records.
+func (w *OpenSearchWriter) Flush() error {
+	return w.batch.Flush()
+}
+
+// Close writes the buffered records.
+func (w *OpenSearchWriter) Close() error {
+	return w.Flush()
+}
+
+var _ io.WriteCloser = (*OpenSearchWriter)(nil)
+
+func (w *OpenSearchWriter) bulk(ctx context.Context, records []httpRecord) error {
+	body := new(bytes.Buffer)
+	for _, record := range records {
+		fields := record.fields
+
+		action, err := json.Marshal(map[string]any{"index": map[string]string{"_index": w.indexName(fields)}})
+		if err != nil {
+			return err
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(record.raw)
+		body.WriteByte('\n')
+	}
+
+	req, sent, err := w.batch.newRequest(ctx, w.endpoint+"/_bulk", body.Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	if w.sign != nil {
+		if err := w.sign(req, sent); err != nil {
+			return fmt.Errorf("opensearch: signing request: %w", err)
+		}
+	}
+
+	data, err := w.batch.do("opensearch", req)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if json.Unmarshal(data, &result) != nil || !result.Errors {
+		return nil
+	}
+	if len(result.Items) != len(records) {
+		return errors.New("opensearch: bulk request had item errors")
+	}
+
+	partial := &partialSendError{}
+	var reason string
+	for i, item := range result.Items {
+		for _, outcome := range item {
+			switch {
+			case outcome.Status >= 200 && outcome.Status <= 299:
+			case outcome.Status == http.StatusTooManyRequests || outcome.Status >= 500:
+				partial.retry = append(partial.retry, records[i])
+			default:
+				if partial.rejected++; reason == "" {
+					reason = fmt.Sprintf("status %d: %s: %s", outcome.Status, outcome.Error.Type, outcome.Error.Reason)
+				}
+			}
+		}
+	}
+
+	switch {
+	case partial.rejected == 0 && len(partial.retry) == 0:
+		return nil
+	case partial.rejected > 0:
+		partial.err = fmt.Errorf("opensearch: bulk request rejected %d items (%s), retrying %d", partial.rejected, reason, len(partial.retry))
+	default:
+		partial.err = fmt.Errorf("opensearch: bulk request had item errors, retrying %d", len(partial.retry))
+	}
+	return partial
+}
+
+// indexName returns the index the decoded record is written to.
+func (w *OpenSearchWriter) indexName(fields map[string]any) string {
+	var function, version string
+	if lambda, ok := fields[kLambdaRecord].(map[string]any); ok {
+		function, _ = lambda[kLambdaFunctionName].(string)
+		version, _ = lambda[kLambdaFunctionVersion].(string)
+	}
+	logType, _ := fields[kLambdaLogType].(string)
+
+	return strings.NewReplacer(
+		"{function}", strings.ToLower(function),
+		"{version}", strings.ToLower(version),
+		"{type}", strings.ToLower(logType),
+		"{date}", recordTime(fields).UTC().Format("2006.01.02"),
+	).Replace(w.index)
+}
@@ -0,0 +1,129 @@
+package sloglambda_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSearchWriter(t *testing.T) {
+	var (
+		bodies    []string
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		signature = r.Header.Get("Authorization")
+
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	w := sloglambda.NewOpenSearchWriter(server.URL + "/").
+		WithIndex("logs-{type}-{date}").
+		WithBatchSize(2).
+		WithSigner(func(req *http.Request, body []byte) error {
+			req.Header.Set("Authorization", "signed:"+strconv.Itoa(strings.Count(string(body), "\n")))
+			return nil
+		})
+	logger := slog.New(sloglambda.NewHandler(w, sloglambda.WithJSON()))
+
+	when := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	record := slog.NewRecord(when, slog.LevelInfo, "one", 0)
+	require.NoError(t, logger.Handler().Handle(context.Background(), record))
+	assert.Empty(t, bodies)
+
+	record = slog.NewRecord(when, slog.LevelInfo, "two", 0)
+	require.NoError(t, logger.Handler().Handle(context.Background(), record))
+	require.NoError(t, w.Flush())
+
+	require.Len(t, bodies, 1)
+	lines := strings.Split(strings.TrimSuffix(bodies[0], "\n"), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, `{"index":{"_index":"logs-app.log-2024.03.04"}}`, lines[0])
+	assert.Contains(t, lines[1], `"msg":"one"`)
+	assert.Contains(t, lines[3], `"msg":"two"`)
+	assert.Equal(t, "signed:4", signature)
+
+	t.Run("reports item errors", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"errors":true}`))
+		}))
+		defer failing.Close()
+
+		w := sloglambda.NewOpenSearchWriter(failing.URL)
+		_, err := w.Write([]byte(`{"msg":"x"}` + "\n"))
+		require.NoError(t, err)
+
+		assert.EqualError(t, w.Flush(), "opensearch: bulk request had item errors")
+	})
+
+	t.Run("retries only the items that can be retried", func(t *testing.T) {
+		var bodies []string
+		mixed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) > 1 {
+				_, _ = w.Write([]byte(`{"errors":false,"items":[{"index":{"status":201}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"errors":true,"items":[` +
+				`{"index":{"status":201}},` +
+				`{"index":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"queue full"}}},` +
+				`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+		}))
+		defer mixed.Close()
+
+		var reported []error
+		w := sloglambda.NewOpenSearchWriter(mixed.URL)
+		logger := slog.New(sloglambda.NewHandler(w, sloglambda.WithJSON(), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			reported = append(reported, err)
+		})))
+		logger.Info("indexed")
+		logger.Info("throttled")
+		logger.Info("malformed")
+
+		assert.EqualError(t, w.Flush(), "opensearch: bulk request rejected 1 items (status 400: mapper_parsing_exception: failed to parse), retrying 1")
+		require.NoError(t, w.Flush())
+
+		require.Len(t, bodies, 2)
+		assert.NotContains(t, bodies[1], `"msg":"indexed"`)
+		assert.Contains(t, bodies[1], `"msg":"throttled"`)
+		assert.NotContains(t, bodies[1], `"msg":"malformed"`)
+		assert.Empty(t, reported)
+	})
+
+	t.Run("reports items rejected by background sends", func(t *testing.T) {
+		rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+		}))
+		defer rejecting.Close()
+
+		var reported []error
+		w := sloglambda.NewOpenSearchWriter(rejecting.URL).WithBatchSize(1)
+
+		handler := sloglambda.NewHandler(w, sloglambda.WithJSON(), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			reported = append(reported, err)
+		}))
+
+		slog.New(handler).Info("malformed")
+		assert.Error(t, handler.EndInvocation(context.Background()))
+
+		require.Len(t, reported, 1)
+		assert.ErrorContains(t, reported[0], "opensearch: bulk request rejected 1 items")
+	})
+}
@@ -0,0 +1,81 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	kOperation         = "operation"
+	kOperationDuration = "durationMs"
+	kOperationStatus   = "status"
+	kOperationError    = "error"
+)
+
+// Operation times a unit of work and logs a record describing it when it ends, giving span-like
+// timing without a tracing backend.
+type Operation struct {
+	ctx   context.Context
+	name  string
+	start time.Time
+	ended atomic.Bool
+}
+
+// StartOperation starts timing the operation with the given name.
+//
+// The record is written with the logger from ctx (see LoggerFromContext) and with ctx itself, so it
+// is nested under the scopes opened with ctx (see Scope).
+func StartOperation(ctx context.Context, name string) *Operation {
+	return &Operation{
+		ctx:   ctx,
+		name:  name,
+		start: time.Now(),
+	}
+}
+
+// End logs an "operation finished" record with the "operation" name, its "durationMs", and a
+// "status" of "ok", or of "error" along with the "error" if err is not nil. Failed operations are
+// logged at ERROR, everything else at INFO.
+//
+// Only the first call to End logs a record, so it is safe to defer End as a fallback. It returns
+// the duration of the operation.
+func (o *Operation) End(err error) time.Duration {
+	duration := time.Since(o.start)
+	if !o.ended.CompareAndSwap(false, true) {
+		return duration
+	}
+
+	logger := LoggerFromContext(o.ctx)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+	}
+
+	if !logger.Enabled(o.ctx, level) {
+		return duration
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, "operation finished", pcs[0])
+	record.AddAttrs(
+		slog.String(kOperation, o.name),
+		slog.Int64(kOperationDuration, duration.Milliseconds()),
+	)
+	if err != nil {
+		record.AddAttrs(
+			slog.String(kOperationStatus, "error"),
+			slog.String(kOperationError, err.Error()),
+		)
+	} else {
+		record.AddAttrs(slog.String(kOperationStatus, "ok"))
+	}
+
+	_ = logger.Handler().Handle(o.ctx, record)
+
+	return duration
+}
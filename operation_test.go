@@ -0,0 +1,68 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartOperation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(sloglambda.NewHandler(&buf, sloglambda.WithJSON(), sloglambda.WithoutTime()))
+	ctx := sloglambda.ContextWithLogger(context.Background(), logger)
+
+	decode := func(t *testing.T) map[string]any {
+		t.Helper()
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		return out
+	}
+
+	t.Run("success", func(t *testing.T) {
+		buf.Reset()
+		sloglambda.StartOperation(ctx, "load").End(nil)
+
+		out := decode(t)
+		assert.Equal(t, "INFO", out["level"])
+		assert.Equal(t, "operation finished", out["msg"])
+		assert.Equal(t, "load", out["operation"])
+		assert.Equal(t, "ok", out["status"])
+		assert.Contains(t, out, "durationMs")
+		assert.NotContains(t, out, "error")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		buf.Reset()
+		sloglambda.StartOperation(ctx, "save").End(errors.New("conflict"))
+
+		out := decode(t)
+		assert.Equal(t, "ERROR", out["level"])
+		assert.Equal(t, "error", out["status"])
+		assert.Equal(t, "conflict", out["error"])
+	})
+
+	t.Run("logs once", func(t *testing.T) {
+		buf.Reset()
+		op := sloglambda.StartOperation(ctx, "once")
+		op.End(nil)
+		op.End(errors.New("ignored"))
+
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+	})
+
+	t.Run("nests under the scope", func(t *testing.T) {
+		buf.Reset()
+		sloglambda.StartOperation(sloglambda.Scope(ctx, "checkout"), "charge").End(nil)
+
+		out := decode(t)
+		require.IsType(t, map[string]any{}, out["checkout"])
+		assert.Equal(t, "charge", out["checkout"].(map[string]any)["operation"])
+	})
+}
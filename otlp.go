@@ -0,0 +1,405 @@
+package sloglambda
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOTLPBatchSize is the default number of records an OTLPWriter buffers before sending.
+const DefaultOTLPBatchSize = 1000
+
+// OTLPWriter is an io.Writer that sends the records written by a Handler to an OpenTelemetry
+// (OTLP/HTTP JSON) traces endpoint, such as Honeycomb, as one trace per invocation.
+//
+// The Handler must write JSON (see WithJSON). The records of each invocation are buffered and
+// sent when the Handler ends the invocation (see Handler.EndInvocation and HTTPMiddleware), on
+// Flush or Close, or when the batch is full. Each invocation becomes a root span named after the
+// function, with its records attached as span events carrying the record's fields as attributes.
+// The span is marked as an error when any record is at ERROR or above.
+//
+// The span uses the record's trace ID when the Handler writes one (see WithTraceContext), otherwise
+// the X-Ray trace ID of the invocation, otherwise one derived from the request ID, so the records of
+// an invocation sent in separate batches belong to the same trace. The span covers the invocation
+// from its start (see ContextWithInvocationStart) until the Handler ends it, falling back to the
+// times of its first and last records.
+type OTLPWriter struct {
+	endpoint string
+	headers  map[string]string
+	batch    *httpBatch
+
+	mu          sync.Mutex
+	invocations map[string]*otlpInvocation
+	order       []string
+}
+
+// otlpInvocation is what an OTLPWriter learned about an invocation from the contexts its records
+// were written and flushed with, and the ID of the span every export of the invocation uses.
+type otlpInvocation struct {
+	spanID     string
+	traceID    string
+	start, end time.Time
+}
+
+// NewOTLPWriter creates an OTLPWriter that sends traces to the OTLP/HTTP traces endpoint, for
+// example "https://api.honeycomb.io/v1/traces".
+func NewOTLPWriter(endpoint string) *OTLPWriter {
+	w := &OTLPWriter{endpoint: endpoint, invocations: make(map[string]*otlpInvocation)}
+	w.batch = newHTTPBatch("otlp", w.export)
+	w.batch.batchSize = DefaultOTLPBatchSize
+	return w
+}
+
+// WithHeaders configures headers added to every request, such as an API key.
+func (w *OTLPWriter) WithHeaders(headers map[string]string) *OTLPWriter {
+	w.headers = headers
+	return w
+}
+
+// WithClient configures the http.Client used to send traces.
+func (w *OTLPWriter) WithClient(client *http.Client) *OTLPWriter {
+	w.batch.client = client
+	return w
+}
+
+// WithBatchSize configures the number of records buffered before they are sent.
+func (w *OTLPWriter) WithBatchSize(size int) *OTLPWriter {
+	w.batch.batchSize = size
+	return w
+}
+
+// WithTimeout configures how long the OTLPWriter waits for traces to be sent.
+func (w *OTLPWriter) WithTimeout(timeout time.Duration) *OTLPWriter {
+	w.batch.timeout = timeout
+	return w
+}
+
+// WithGzip configures the OTLPWriter to gzip compress batches whose payload is at least minSize
+// bytes, trading CPU time for fewer bytes sent. A minSize of zero compresses every batch.
+func (w *OTLPWriter) WithGzip(minSize int) *OTLPWriter {
+	w.batch.withGzip(minSize)
+	return w
+}
+
+// Write implements io.Writer.
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	return w.batch.write(p)
+}
+
+// Flush sends the buffered records.
+func (w *OTLPWriter) Flush() error {
+	return w.batch.Flush()
+}
+
+// Close sends the buffered records.
+func (w *OTLPWriter) Close() error {
+	return w.Flush()
+}
+
+var _ io.WriteCloser = (*OTLPWriter)(nil)
+
+func (w *OTLPWriter) writeContext(ctx context.Context, p []byte) error {
+	w.observe(ctx)
+	_, err := w.batch.write(p)
+	return err
+}
+
+func (w *OTLPWriter) flushContext(ctx context.Context) error {
+	w.observe(ctx)
+	return w.Flush()
+}
+
+// observe records the X-Ray trace ID, start, and end of the invocation ctx belongs to.
+func (w *OTLPWriter) observe(ctx context.Context) {
+	requestID := requestIDFromContext(ctx)
+	if requestID == "" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	inv := w.track(requestID)
+	if traceID := xrayTraceID(xrayTraceHeader(ctx)); traceID != "" {
+		inv.traceID = traceID
+	}
+	if start, ok := ctx.Value(invocationStartKey{}).(time.Time); ok {
+		inv.start = start
+	}
+	if end, ok := invocationEnd(ctx); ok {
+		inv.end = end
+	}
+}
+
+// invocation returns what the OTLPWriter knows about the invocation with the request ID, assigning
+// the invocation's span ID on first use.
+func (w *OTLPWriter) invocation(requestID string) otlpInvocation {
+	if requestID == "" {
+		return otlpInvocation{spanID: randomHex(8)}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	inv := w.track(requestID)
+	if inv.spanID == "" {
+		inv.spanID = randomHex(8)
+	}
+	return *inv
+}
+
+// track returns the state of the invocation with the request ID, creating it and forgetting the
+// oldest invocation if needed. It must be called with w.mu held.
+func (w *OTLPWriter) track(requestID string) *otlpInvocation {
+	inv, ok := w.invocations[requestID]
+	if !ok {
+		if len(w.order) >= maxTrackedInvocations {
+			delete(w.invocations, w.order[0])
+			w.order = w.order[1:]
+		}
+		inv = new(otlpInvocation)
+		w.invocations[requestID] = inv
+		w.order = append(w.order, requestID)
+	}
+	return inv
+}
+
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events"`
+	Status            struct {
+		Code int `json:"code"`
+	} `json:"status"`
+
+	start, end time.Time
+	ended      bool
+}
+
+const (
+	otlpSpanKindServer  = 2
+	otlpStatusCodeError = 2
+)
+
+func (w *OTLPWriter) export(ctx context.Context, records []httpRecord) error {
+	spans := make(map[string]*otlpSpan)
+	var order []string
+	var service string
+
+	for _, record := range records {
+		fields := record.fields
+
+		requestID, function := recordInvocation(fields)
+		if service == "" {
+			service = function
+		}
+
+		span, ok := spans[requestID]
+		if !ok {
+			inv := w.invocation(requestID)
+			span = &otlpSpan{
+				TraceID: inv.traceID,
+				SpanID:  inv.spanID,
+				Name:    function,
+				Kind:    otlpSpanKindServer,
+				start:   inv.start,
+				end:     inv.end,
+				ended:   !inv.end.IsZero(),
+			}
+			if span.TraceID == "" {
+				span.TraceID = otlpTraceID(requestID)
+			}
+			if span.Name == "" {
+				span.Name = "invocation"
+			}
+			if requestID != "" {
+				span.Attributes = []otlpAttribute{otlpAttr("faas.invocation_id", requestID)}
+			}
+			spans[requestID] = span
+			order = append(order, requestID)
+		}
+		if traceID, ok := fields[kTraceID].(string); ok && len(traceID) == 32 {
+			span.TraceID = traceID
+		}
+
+		at := recordTime(fields)
+		if span.start.IsZero() || at.Before(span.start) {
+			span.start = at
+		}
+		if !span.ended && at.After(span.end) {
+			span.end = at
+		}
+
+		if level, _ := fields[slog.LevelKey].(string); strings.HasPrefix(level, "ERROR") || strings.HasPrefix(level, "FATAL") {
+			span.Status.Code = otlpStatusCodeError
+		}
+
+		name, _ := fields[slog.MessageKey].(string)
+		if message, ok := fields[kInsightsMessage].(string); ok {
+			name = message
+		}
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(at.UnixNano(), 10),
+			Name:         name,
+			Attributes:   otlpAttributes("", fields),
+		})
+	}
+
+	resource := []otlpAttribute{otlpAttr("cloud.provider", "aws"), otlpAttr("cloud.platform", "aws_lambda")}
+	if service != "" {
+		resource = append(resource, otlpAttr("service.name", service))
+	}
+
+	result := make([]*otlpSpan, 0, len(order))
+	for _, requestID := range order {
+		span := spans[requestID]
+		span.StartTimeUnixNano = strconv.FormatInt(span.start.UnixNano(), 10)
+		span.EndTimeUnixNano = strconv.FormatInt(max(span.end.UnixNano(), span.start.UnixNano()), 10)
+		result = append(result, span)
+	}
+
+	payload := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{"attributes": resource},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": "github.com/maddiesch/slog-lambda"},
+				"spans": result,
+			}},
+		}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, _, err := w.batch.newRequest(ctx, w.endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	_, err = w.batch.do("otlp", req)
+	return err
+}
+
+// otlpAttributes flattens the decoded record into attributes with dot separated keys.
+func otlpAttributes(prefix string, fields map[string]any) []otlpAttribute {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var attrs []otlpAttribute
+	for _, k := range keys {
+		if group, ok := fields[k].(map[string]any); ok {
+			attrs = append(attrs, otlpAttributes(prefix+k+".", group)...)
+			continue
+		}
+		attrs = append(attrs, otlpAttr(prefix+k, fields[k]))
+	}
+	return attrs
+}
+
+func otlpAttr(key string, value any) otlpAttribute {
+	switch v := value.(type) {
+	case string:
+		return otlpAttribute{Key: key, Value: map[string]any{"stringValue": v}}
+	case bool:
+		return otlpAttribute{Key: key, Value: map[string]any{"boolValue": v}}
+	case float64:
+		if v == float64(int64(v)) {
+			return otlpAttribute{Key: key, Value: map[string]any{"intValue": strconv.FormatInt(int64(v), 10)}}
+		}
+		return otlpAttribute{Key: key, Value: map[string]any{"doubleValue": v}}
+	default:
+		b, _ := json.Marshal(v)
+		return otlpAttribute{Key: key, Value: map[string]any{"stringValue": string(b)}}
+	}
+}
+
+// xrayTraceHeaderKey is the context key aws-lambda-go stores the X-Ray trace header of an
+// invocation under.
+const xrayTraceHeaderKey = "x-amzn-trace-id"
+
+// xrayTraceHeader returns the X-Ray trace header of the invocation ctx belongs to.
+func xrayTraceHeader(ctx context.Context) string {
+	if header, ok := ctx.Value(xrayTraceHeaderKey).(string); ok && header != "" {
+		return header
+	}
+	return os.Getenv("_X_AMZN_TRACE_ID")
+}
+
+// xrayTraceID converts the root trace ID of an X-Ray trace header, such as
+// "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1", to an OpenTelemetry trace ID.
+func xrayTraceID(header string) string {
+	for _, field := range strings.Split(header, ";") {
+		root, ok := strings.CutPrefix(strings.TrimSpace(field), "Root=1-")
+		if !ok {
+			continue
+		}
+		epoch, id, _ := strings.Cut(root, "-")
+		if traceID := epoch + id; len(epoch) == 8 && isHex(traceID, 32) {
+			return strings.ToLower(traceID)
+		}
+	}
+	return ""
+}
+
+// otlpTraceID derives a trace ID from a request ID, using the request ID itself when it is a UUID.
+func otlpTraceID(requestID string) string {
+	if requestID == "" {
+		return randomHex(16)
+	}
+	if id := strings.ReplaceAll(requestID, "-", ""); isHex(id, 32) {
+		return strings.ToLower(id)
+	}
+	sum := sha256.Sum256([]byte(requestID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// isHex reports whether s is n hexadecimal digits.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
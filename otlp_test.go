@@ -0,0 +1,160 @@
+package sloglambda_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPWriter(t *testing.T) {
+	type attribute struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+	var export struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []attribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID    string      `json:"traceId"`
+					SpanID     string      `json:"spanId"`
+					Name       string      `json:"name"`
+					Attributes []attribute `json:"attributes"`
+					Events     []struct {
+						Name       string      `json:"name"`
+						Attributes []attribute `json:"attributes"`
+					} `json:"events"`
+					Status struct {
+						Code int `json:"code"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "secret", r.Header.Get("X-Honeycomb-Team"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+	}))
+	defer server.Close()
+
+	w := sloglambda.NewOTLPWriter(server.URL).WithHeaders(map[string]string{"X-Honeycomb-Team": "secret"})
+	handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+	logger := slog.New(handler)
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "otlp-1"})
+	logger.InfoContext(ctx, "started", "items", 3)
+	logger.ErrorContext(ctx, "failed", "retry", false)
+
+	require.NoError(t, handler.EndInvocation(ctx))
+	require.Equal(t, 1, requests)
+
+	require.Len(t, export.ResourceSpans, 1)
+	assert.Contains(t, export.ResourceSpans[0].Resource.Attributes, attribute{Key: "service.name", Value: map[string]any{"stringValue": "test-function"}})
+
+	spans := export.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	span := spans[0]
+
+	assert.Equal(t, "test-function", span.Name)
+	assert.Len(t, span.TraceID, 32)
+	assert.Len(t, span.SpanID, 16)
+	assert.Equal(t, 2, span.Status.Code)
+	assert.Contains(t, span.Attributes, attribute{Key: "faas.invocation_id", Value: map[string]any{"stringValue": "otlp-1"}})
+
+	require.Len(t, span.Events, 2)
+	assert.Equal(t, "started", span.Events[0].Name)
+	assert.Contains(t, span.Events[0].Attributes, attribute{Key: "items", Value: map[string]any{"intValue": "3"}})
+	assert.Contains(t, span.Events[0].Attributes, attribute{Key: "record.requestId", Value: map[string]any{"stringValue": "otlp-1"}})
+	assert.Contains(t, span.Events[1].Attributes, attribute{Key: "retry", Value: map[string]any{"boolValue": false}})
+}
+
+func TestOTLPWriterInvocation(t *testing.T) {
+	type span struct {
+		TraceID           string `json:"traceId"`
+		SpanID            string `json:"spanId"`
+		StartTimeUnixNano string `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string `json:"endTimeUnixNano"`
+	}
+	var spans []span
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var export struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []span `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+		spans = append(spans, export.ResourceSpans[0].ScopeSpans[0].Spans...)
+	}))
+	defer server.Close()
+
+	invocation := func(requestID string) context.Context {
+		return lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: requestID})
+	}
+
+	t.Run("one trace and span per invocation", func(t *testing.T) {
+		spans = nil
+		w := sloglambda.NewOTLPWriter(server.URL)
+		handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+		ctx := invocation("0b3e1c2a-5d4f-4e6a-9b8c-7d6e5f4a3b2c")
+
+		slog.New(handler).InfoContext(ctx, "first batch")
+		require.NoError(t, w.Flush())
+		slog.New(handler).InfoContext(ctx, "second batch")
+		require.NoError(t, handler.EndInvocation(ctx))
+
+		require.Len(t, spans, 2)
+		assert.Equal(t, "0b3e1c2a5d4f4e6a9b8c7d6e5f4a3b2c", spans[0].TraceID)
+		assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+		assert.Len(t, spans[0].SpanID, 16)
+		assert.Equal(t, spans[0].SpanID, spans[1].SpanID, "one span for the invocation across batches")
+	})
+
+	t.Run("trace ID from the X-Ray trace header", func(t *testing.T) {
+		spans = nil
+		w := sloglambda.NewOTLPWriter(server.URL)
+		handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+		ctx := context.WithValue(invocation("otlp-xray"), "x-amzn-trace-id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+		slog.New(handler).InfoContext(ctx, "traced")
+		require.NoError(t, handler.EndInvocation(ctx))
+
+		require.Len(t, spans, 1)
+		assert.Equal(t, "5759e988bd862e3fe1be46a994272793", spans[0].TraceID)
+	})
+
+	t.Run("span covers the invocation", func(t *testing.T) {
+		spans = nil
+		w := sloglambda.NewOTLPWriter(server.URL)
+		handler := sloglambda.NewHandler(w, sloglambda.WithJSON())
+		start := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+		ctx := sloglambda.ContextWithInvocationStart(invocation("otlp-timing"), start)
+
+		slog.New(handler).InfoContext(ctx, "working")
+		time.Sleep(time.Millisecond)
+		before := time.Now()
+		require.NoError(t, handler.EndInvocation(ctx))
+
+		require.Len(t, spans, 1)
+		assert.Equal(t, strconv.FormatInt(start.UnixNano(), 10), spans[0].StartTimeUnixNano)
+		end, err := strconv.ParseInt(spans[0].EndTimeUnixNano, 10, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, end, before.UnixNano())
+	})
+}
@@ -0,0 +1,157 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"strings"
+	"sync"
+)
+
+// output is an additional destination of a Handler's records, configured with WithOutput.
+type output struct {
+	json   bool
+	sink   Sink
+	mu     *sync.Mutex
+	level  slog.Leveler
+	redact []string
+}
+
+// OutputOption configures an output added with WithOutput.
+type OutputOption func(*output)
+
+// OutputLevel configures the minimum level of the records written to an output.
+//
+// Records are only passed to outputs once they are enabled for the Handler, so a level below the
+// Handler's level (see WithLevel) has no effect.
+func OutputLevel(level slog.Leveler) OutputOption {
+	return func(o *output) {
+		o.level = level
+	}
+}
+
+// OutputRedact configures fields whose values are replaced by "[REDACTED]" in the records written to
+// an output, such as fields that may be written to stdout but not to a third party. Fields are
+// named by their dot separated path, for example "user.email".
+func OutputRedact(paths ...string) OutputOption {
+	return func(o *output) {
+		o.redact = append(o.redact, paths...)
+	}
+}
+
+// WithOutput configures the Handler to also write every record to w in the given format, for
+// example compact JSON to stdout for CloudWatch and text to a buffer shown in development error
+// responses:
+//
+//	var dev bytes.Buffer
+//	handler := sloglambda.NewHandler(os.Stdout, sloglambda.WithJSON(), sloglambda.WithOutput(sloglambda.FormatText, &dev))
+//
+// Each output can have its own level and fields to redact (see OutputLevel and OutputRedact). The
+// option can be given more than once. Each record is encoded separately for every output, with
+// the terminator and text header of the Handler (see WithTerminator and WithTextHeader), and written
+// synchronously, even when the Handler is asynchronous (see WithAsync). Errors writing to an output
+// are reported to the error handler (see WithErrorHandler). Outputs are flushed and closed along
+// with the Handler's sink.
+func WithOutput(format Format, w io.Writer, options ...OutputOption) Option {
+	return func(h *Handler) {
+		if format != FormatJSON && format != FormatText {
+			h.invalidOption("WithOutput: invalid format %q", format)
+			return
+		}
+		if w == nil {
+			h.invalidOption("WithOutput: nil writer")
+			return
+		}
+		out := &output{
+			json: format == FormatJSON,
+			sink: WriterSink(w),
+			mu:   new(sync.Mutex),
+		}
+		for _, opt := range options {
+			opt(out)
+		}
+		if out.level == nil {
+			out.level = slog.Level(math.MinInt)
+		}
+		h.outputs = append(h.outputs[:len(h.outputs):len(h.outputs)], out)
+	}
+}
+
+// writeOutputs encodes the record for each of the Handler's additional outputs that accepts its
+// level and writes it.
+func (h *Handler) writeOutputs(ctx context.Context, level slog.Level, record logRecord) {
+	if len(h.outputs) == 0 {
+		return
+	}
+
+	buf := h.buffers.get()
+	defer h.buffers.put(buf)
+
+	for _, out := range h.outputs {
+		if level < out.level.Level() {
+			continue
+		}
+
+		buf.Reset()
+		if err := h.encodeWith(h.formatEncoder(out.json), buf, redactFields(record, out.redact)); err != nil {
+			h.reportError(ctx, fmt.Errorf("output: %w", err))
+			continue
+		}
+		if err := out.write(ctx, buf); err != nil {
+			h.reportError(ctx, fmt.Errorf("output: %w", err))
+		}
+	}
+}
+
+func (o *output) write(ctx context.Context, buf *bytes.Buffer) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.sink.Write(ctx, buf.Bytes())
+}
+
+// flushOutputs flushes the Handler's additional outputs.
+func (h *Handler) flushOutputs(ctx context.Context) error {
+	var errs []error
+	for _, out := range h.outputs {
+		errs = append(errs, out.sink.Flush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// closeOutputs closes the Handler's additional outputs.
+func (h *Handler) closeOutputs() error {
+	var errs []error
+	for _, out := range h.outputs {
+		errs = append(errs, out.sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// redactFields returns a copy of record with the values at the given paths replaced by the redacted
+// placeholder. Only the groups along the paths are copied; record is not modified.
+func redactFields(record logRecord, paths []string) logRecord {
+	for _, path := range paths {
+		if _, ok := record.lookup(path); ok {
+			record = redactField(record, path)
+		}
+	}
+	return record
+}
+
+func redactField(record logRecord, path string) logRecord {
+	record = maps.Clone(record)
+
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		record[key] = redactedPlaceholder
+		return record
+	}
+	record[key] = redactField(record[key].(logRecord), rest)
+	return record
+}
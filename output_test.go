@@ -0,0 +1,94 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("unavailable")
+}
+
+func TestWithOutput(t *testing.T) {
+	t.Run("writes each format", func(t *testing.T) {
+		var stdout, dev bytes.Buffer
+		logger := slog.New(sloglambda.NewHandler(&stdout,
+			sloglambda.WithJSON(),
+			sloglambda.WithoutTime(),
+			sloglambda.WithOutput(sloglambda.FormatText, &dev),
+		))
+
+		logger.Info("Hello, world!", "user", "alice")
+
+		assert.Equal(t, `{"level":"INFO","msg":"Hello, world!","record":{"functionName":"test-function","version":"$LATEST"},"type":"app.log","user":"alice"}`+"\n", stdout.String())
+		assert.Equal(t, `level="INFO" msg="Hello, world!" record.functionName="test-function" record.version="$LATEST" type="app.log" user="alice"`+"\n", dev.String())
+	})
+
+	t.Run("flushed at the end of the invocation", func(t *testing.T) {
+		out := new(lineRecorder)
+		handler := sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithOutput(sloglambda.FormatJSON, sloglambda.NewBatchWriter(out, 1<<20, 0)))
+
+		slog.New(handler).Info("buffered")
+		assert.Empty(t, out.writes)
+
+		require.NoError(t, handler.EndInvocation(context.Background()))
+		assert.Len(t, out.writes, 1)
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		var stdout bytes.Buffer
+		var reported []error
+		logger := slog.New(sloglambda.NewHandler(&stdout,
+			sloglambda.WithOutput(sloglambda.FormatJSON, failingWriter{}),
+			sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+				reported = append(reported, err)
+			}),
+		))
+
+		logger.Info("Hello, world!")
+
+		assert.NotEmpty(t, stdout.String())
+		require.Len(t, reported, 1)
+		assert.ErrorContains(t, reported[0], "unavailable")
+	})
+
+	t.Run("per output level and redaction", func(t *testing.T) {
+		var stdout, vendor bytes.Buffer
+		logger := slog.New(sloglambda.NewHandler(&stdout,
+			sloglambda.WithJSON(),
+			sloglambda.WithOutput(sloglambda.FormatJSON, &vendor,
+				sloglambda.OutputLevel(slog.LevelWarn),
+				sloglambda.OutputRedact("user.email", "token", "missing.path"),
+			),
+		))
+
+		logger.Info("skipped")
+		logger.Warn("login failed", slog.Group("user", "email", "alice@example.com", "id", 1), "token", "secret")
+
+		assert.Contains(t, stdout.String(), `"msg":"skipped"`)
+		assert.Contains(t, stdout.String(), `"user":{"email":"alice@example.com","id":1}`)
+		assert.Contains(t, stdout.String(), `"token":"secret"`)
+
+		assert.NotContains(t, vendor.String(), "skipped")
+		assert.Contains(t, vendor.String(), `"user":{"email":"[REDACTED]","id":1}`)
+		assert.Contains(t, vendor.String(), `"token":"[REDACTED]"`)
+		assert.NotContains(t, vendor.String(), "missing")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithOutput("yaml", new(bytes.Buffer)))
+		assert.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+
+		_, err = sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithOutput(sloglambda.FormatJSON, nil))
+		assert.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
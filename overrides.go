@@ -0,0 +1,187 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// LevelOverrides holds minimum levels that apply to records matching a message pattern or an
+// attribute value instead of the Handler's level, for example to write DEBUG records from a single
+// subsystem while the Handler's level is INFO.
+//
+// Rules can be changed at any time and are safe for concurrent use.
+type LevelOverrides struct {
+	mu       sync.RWMutex
+	messages map[string]slog.Level
+	attrs    map[attrMatch]slog.Level
+}
+
+type attrMatch struct {
+	key   string
+	value string
+}
+
+// NewLevelOverrides creates an empty set of LevelOverrides.
+func NewLevelOverrides() *LevelOverrides {
+	return &LevelOverrides{
+		messages: make(map[string]slog.Level),
+		attrs:    make(map[attrMatch]slog.Level),
+	}
+}
+
+// SetMessage sets the minimum level of records whose message matches the pattern.
+//
+// A pattern ending in "*" matches messages starting with the rest of the pattern, for example
+// "sql.*" matches "sql.query"; any other pattern must match the message exactly. When several
+// patterns match, the longest one applies.
+func (o *LevelOverrides) SetMessage(pattern string, level slog.Level) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages[pattern] = level
+}
+
+// SetAttr sets the minimum level of records with an attribute named key whose value, formatted as
+// a string, equals value. Attribute rules take precedence over message rules.
+//
+// Attributes are matched by key regardless of the group they belong to.
+func (o *LevelOverrides) SetAttr(key, value string, level slog.Level) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.attrs[attrMatch{key, value}] = level
+}
+
+// Remove removes the message rule with the given pattern.
+func (o *LevelOverrides) Remove(pattern string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.messages, pattern)
+}
+
+// RemoveAttr removes the attribute rule for the given key and value.
+func (o *LevelOverrides) RemoveAttr(key, value string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.attrs, attrMatch{key, value})
+}
+
+// Clear removes every rule.
+func (o *LevelOverrides) Clear() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	clear(o.messages)
+	clear(o.attrs)
+}
+
+// minLevel returns the lowest level of any rule, or ok false if there are no rules.
+func (o *LevelOverrides) minLevel() (level slog.Level, ok bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, l := range o.messages {
+		if !ok || l < level {
+			level, ok = l, true
+		}
+	}
+	for _, l := range o.attrs {
+		if !ok || l < level {
+			level, ok = l, true
+		}
+	}
+	return level, ok
+}
+
+// level returns the minimum level for the record, or ok false if no rule matches it.
+func (o *LevelOverrides) level(gattr []groupOrAttrs, record slog.Record) (level slog.Level, ok bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if len(o.attrs) > 0 {
+		match := func(a slog.Attr) bool {
+			level, ok = o.matchAttr(a)
+			return !ok
+		}
+		for _, ga := range gattr {
+			for _, a := range ga.attrs {
+				if !match(a) {
+					return level, ok
+				}
+			}
+		}
+		record.Attrs(match)
+		if ok {
+			return level, ok
+		}
+	}
+
+	longest := -1
+	for pattern, l := range o.messages {
+		if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard {
+			if strings.HasPrefix(record.Message, prefix) && len(prefix) > longest {
+				level, ok, longest = l, true, len(prefix)
+			}
+		} else if record.Message == pattern && len(pattern) >= longest {
+			level, ok, longest = l, true, len(pattern)
+		}
+	}
+	return level, ok
+}
+
+func (o *LevelOverrides) matchAttr(a slog.Attr) (slog.Level, bool) {
+	if _, ok := a.Value.Any().(*lazyValue); ok {
+		return 0, false
+	}
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			if level, ok := o.matchAttr(ga); ok {
+				return level, ok
+			}
+		}
+		return 0, false
+	}
+
+	level, ok := o.attrs[attrMatch{a.Key, a.Value.String()}]
+	return level, ok
+}
+
+// WithLevelOverrides configures the Handler to apply the given LevelOverrides. Records matching a
+// rule are written when they are at or above the rule's level, regardless of the Handler's level.
+func WithLevelOverrides(overrides *LevelOverrides) Option {
+	return func(h *Handler) {
+		if overrides == nil {
+			h.invalidOption("WithLevelOverrides: nil overrides")
+			return
+		}
+		h.overrides = overrides
+	}
+}
+
+// enabled reports whether a record at the level could be written, taking the level overrides into
+// account.
+func (h *Handler) enabled(level slog.Level) bool {
+	if level >= h.level.Level() {
+		return true
+	}
+	if h.overrides == nil {
+		return false
+	}
+	lowest, ok := h.overrides.minLevel()
+	return ok && level >= lowest
+}
+
+// overridden reports whether the level overrides allow the record to be written.
+func (h *Handler) overridden(record slog.Record) bool {
+	if h.overrides == nil {
+		return true
+	}
+	if level, ok := h.overrides.level(h.gattr, record); ok {
+		return record.Level >= level
+	}
+	return record.Level >= h.level.Level()
+}
@@ -0,0 +1,52 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLevelOverrides(t *testing.T) {
+	overrides := sloglambda.NewLevelOverrides()
+	overrides.SetMessage("sql.*", slog.LevelDebug)
+	overrides.SetMessage("sql.noisy*", slog.LevelWarn)
+	overrides.SetAttr("component", "cache", slog.LevelDebug)
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithoutTime(), sloglambda.WithLevel(slog.LevelInfo), sloglambda.WithLevelOverrides(overrides)))
+
+	logger.Debug("sql.query")
+	logger.Debug("http.request")
+	logger.Info("sql.noisy.ping")
+	logger.Warn("sql.noisy.slow")
+	logger.With("component", "cache").Debug("miss")
+	logger.Debug("hit", slog.Group("ctx", "component", "cache"))
+	logger.Info("http.response")
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		for _, field := range strings.Fields(line) {
+			if msg, ok := strings.CutPrefix(field, "msg="); ok {
+				messages = append(messages, strings.Trim(msg, `"`))
+			}
+		}
+	}
+	assert.Equal(t, []string{"sql.query", "sql.noisy.slow", "miss", "hit", "http.response"}, messages)
+
+	t.Run("changed at runtime", func(t *testing.T) {
+		buffer.Reset()
+		overrides.Remove("sql.*")
+
+		assert.True(t, logger.Enabled(context.Background(), slog.LevelDebug))
+		logger.Debug("sql.query")
+		assert.Empty(t, buffer.String())
+
+		overrides.Clear()
+		assert.False(t, logger.Enabled(context.Background(), slog.LevelDebug))
+	})
+}
@@ -0,0 +1,78 @@
+package sloglambda
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+var (
+	kPanic      = "panic"
+	kPanicType  = "type"
+	kPanicValue = "value"
+	kPanicStack = "stack"
+)
+
+// Panic returns a "panic" group describing a value recovered from a panic, with its Go "type", its
+// "value" formatted as a string (the message of an error), and the "stack" of the calling goroutine.
+// Call it in the deferred function that recovers the panic, so the stack includes the frames that
+// panicked:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			logger.Error("panic recovered", sloglambda.Panic(r))
+//		}
+//	}()
+//
+// Unlike formatting the value with %v, the group keeps the type of values that are not errors, such
+// as strings and structs.
+func Panic(v any) slog.Attr {
+	return slog.Group(kPanic,
+		slog.String(kPanicType, fmt.Sprintf("%T", v)),
+		slog.String(kPanicValue, panicString(v)),
+		slog.String(kPanicStack, string(debug.Stack())),
+	)
+}
+
+func panicString(v any) string {
+	if err, ok := v.(error); ok {
+		return safeString(err.Error)
+	}
+	return safeString(func() string { return fmt.Sprintf("%+v", v) })
+}
+
+// RecoveryMiddleware returns net/http middleware that recovers panics raised by the next handler,
+// logging them at ERROR with the message "panic recovered" and a "panic" group (see Panic), and
+// responding with 500 Internal Server Error.
+//
+// The record is written with the logger from the request context when there is one (see
+// LoggerFromContext), so RecoveryMiddleware installed inside HTTPMiddleware includes the request's
+// fields, and with logger otherwise. http.ErrAbortHandler is re-raised.
+func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				ctx := r.Context()
+				requestLogger := logger
+				if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+					requestLogger = l
+				}
+				requestLogger.LogAttrs(ctx, slog.LevelError, "panic recovered", Panic(v))
+
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicPayload struct {
+	Code int
+}
+
+func TestPanic(t *testing.T) {
+	recovered := func(f func()) (v any) {
+		defer func() { v = recover() }()
+		f()
+		return nil
+	}
+
+	cases := []struct {
+		name      string
+		value     any
+		wantType  string
+		wantValue string
+	}{
+		{"string", "boom", "string", "boom"},
+		{"struct", panicPayload{Code: 7}, "sloglambda_test.panicPayload", "{Code:7}"},
+		{"error", errors.New("failed"), "*errors.errorString", "failed"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+			v := recovered(func() { panic(tc.value) })
+			logger.Error("panic recovered", sloglambda.Panic(v))
+
+			var record struct {
+				Panic map[string]string `json:"panic"`
+			}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+			assert.Equal(t, tc.wantType, record.Panic["type"])
+			assert.Equal(t, tc.wantValue, record.Panic["value"])
+			assert.Contains(t, record.Panic["stack"], "panic_test.go")
+		})
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Run("logs the panic and responds with an error", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		handler := sloglambda.HTTPMiddleware(logger)(sloglambda.RecoveryMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		})))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, buffer.String(), `"msg":"panic recovered"`)
+		assert.Contains(t, buffer.String(), `"http":{"method":"GET","path":"/orders"},"level":"ERROR"`)
+		assert.Contains(t, buffer.String(), `"type":"string","value":"boom"`)
+	})
+
+	t.Run("re-raises http.ErrAbortHandler", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.RecoveryMiddleware(slog.New(sloglambda.NewHandler(buffer)))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Empty(t, buffer.String())
+	})
+}
@@ -0,0 +1,68 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrInvalidRawRecord is returned by Handler.WriteRaw and RawRecord when the record is not a JSON
+// object.
+var ErrInvalidRawRecord = errors.New("invalid raw record")
+
+// RawRecord writes p, an already encoded JSON object, with the Handler of the logger in ctx (see
+// LoggerFromContext and Handler.WriteRaw). It returns an error if that logger is not backed by a
+// Handler.
+func RawRecord(ctx context.Context, p []byte) error {
+	h, ok := LoggerFromContext(ctx).Handler().(*Handler)
+	if !ok {
+		return fmt.Errorf("%w: logger does not use a sloglambda.Handler", ErrInvalidRawRecord)
+	}
+	return h.WriteRaw(ctx, p)
+}
+
+// WriteRaw writes p, an already encoded JSON object, as a record without decoding and encoding it
+// again, for relaying the logs of sub-processes or embedded interpreters that already write JSON.
+//
+// The object is compacted onto a single line and followed by the Handler's terminator (see
+// WithTerminator), but is otherwise written verbatim: the Handler adds no fields, does not apply
+// its level, and ignores its format. Its "level" field, when present, is used for the Handler's
+// statistics. WriteRaw returns ErrInvalidRawRecord if p is not a JSON object, and ErrRecordTooLarge
+// if it exceeds the maximum line size (see WithMaxLineSize).
+func (h *Handler) WriteRaw(ctx context.Context, p []byte) error {
+	p = bytes.TrimSpace(p)
+	if len(p) == 0 || p[0] != '{' || !json.Valid(p) {
+		return ErrInvalidRawRecord
+	}
+
+	buf := h.buffers.get()
+	defer h.buffers.put(buf)
+
+	if err := json.Compact(buf, p); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRawRecord, err)
+	}
+	buf.WriteByte('\n')
+	h.terminate(buf)
+
+	if h.maxLineSize > 0 && buf.Len() > h.maxLineSize {
+		return fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, buf.Len())
+	}
+
+	_, err := h.write(ctx, buf.Bytes(), rawRecordLevel(p), true)
+	return err
+}
+
+// rawRecordLevel returns the level of a raw record, or INFO if it has none.
+func rawRecordLevel(p []byte) slog.Level {
+	var fields struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(p, &fields); err != nil || fields.Level == "" {
+		return slog.LevelInfo
+	}
+	level, _ := ParseLevel(fields.Level)
+	return level
+}
@@ -0,0 +1,62 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRaw(t *testing.T) {
+	t.Run("writes the record verbatim on one line", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithLevel(slog.LevelError))
+
+		err := handler.WriteRaw(context.Background(), []byte("{\n  \"level\": \"WARN\",\n  \"msg\": \"from python\"\n}\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"level":"WARN","msg":"from python"}`+"\n", buffer.String())
+		assert.Equal(t, uint64(1), handler.Stats().Warn)
+	})
+
+	t.Run("uses the terminator", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithTerminator("\r\n"))
+
+		require.NoError(t, handler.WriteRaw(context.Background(), []byte(`{"msg":"hi"}`)))
+		assert.Equal(t, `{"msg":"hi"}`+"\r\n", buffer.String())
+	})
+
+	t.Run("rejects invalid records", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer)
+
+		for _, p := range []string{"", "not json", `["array"]`, `{"msg":`, `"string"`} {
+			assert.ErrorIs(t, handler.WriteRaw(context.Background(), []byte(p)), sloglambda.ErrInvalidRawRecord, p)
+		}
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("rejects records over the maximum line size", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		handler := sloglambda.NewHandler(buffer, sloglambda.WithMaxLineSize(8))
+
+		assert.ErrorIs(t, handler.WriteRaw(context.Background(), []byte(`{"msg":"too long"}`)), sloglambda.ErrRecordTooLarge)
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func TestRawRecord(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	ctx := sloglambda.ContextWithLogger(context.Background(), slog.New(sloglambda.NewHandler(buffer)))
+
+	require.NoError(t, sloglambda.RawRecord(ctx, []byte(`{"msg":"relayed"}`)))
+	assert.Equal(t, `{"msg":"relayed"}`+"\n", buffer.String())
+
+	ctx = sloglambda.ContextWithLogger(context.Background(), slog.New(slog.NewJSONHandler(buffer, nil)))
+	assert.ErrorIs(t, sloglambda.RawRecord(ctx, []byte(`{"msg":"relayed"}`)), sloglambda.ErrInvalidRawRecord)
+}
@@ -0,0 +1,151 @@
+package sloglambda
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"sync"
+)
+
+// FlightRecorder is a slog.Handler that keeps the most recent records of every level in memory, so
+// they can be written out after a failure without paying to ship every record.
+//
+// Records are passed on to the next handler as usual. Independently of the next handler's level,
+// every record is encoded and retained in a fixed size ring buffer that can be written out with
+// DumpTo, automatically when a FATAL record is logged (see DumpOnFatal), or when the function panics
+// (see DumpOnPanic).
+type FlightRecorder struct {
+	next    slog.Handler
+	encoder slog.Handler
+	state   *flightRecorderState
+}
+
+type flightRecorderState struct {
+	mu      sync.Mutex
+	records [][]byte
+	next    int
+	full    bool
+	fatal   io.Writer
+}
+
+// NewFlightRecorder creates a FlightRecorder that retains the last size records and passes every
+// record on to next. The options configure how retained records are encoded.
+func NewFlightRecorder(next slog.Handler, size int, options ...Option) *FlightRecorder {
+	if size < 1 {
+		size = 1
+	}
+
+	state := &flightRecorderState{
+		records: make([][]byte, size),
+	}
+
+	options = append(options[:len(options):len(options)], WithLevel(slog.Level(math.MinInt)))
+
+	return &FlightRecorder{
+		next:    next,
+		encoder: NewHandler(state, options...),
+		state:   state,
+	}
+}
+
+// Write retains a single encoded record, implementing io.Writer for the recorder's encoder.
+func (s *flightRecorderState) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[s.next] = append(s.records[s.next][:0], p...)
+	s.next = (s.next + 1) % len(s.records)
+	if s.next == 0 {
+		s.full = true
+	}
+
+	return len(p), nil
+}
+
+// DumpOnFatal configures the FlightRecorder to write the retained records to w whenever a FATAL
+// record is logged.
+func (f *FlightRecorder) DumpOnFatal(w io.Writer) *FlightRecorder {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	f.state.fatal = w
+	return f
+}
+
+// DumpTo writes the retained records, oldest first, to w.
+func (f *FlightRecorder) DumpTo(w io.Writer) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	return f.state.dumpTo(w)
+}
+
+func (s *flightRecorderState) dumpTo(w io.Writer) error {
+	start := 0
+	if s.full {
+		start = s.next
+	}
+
+	for i := 0; i < len(s.records); i++ {
+		idx := (start + i) % len(s.records)
+		if !s.full && idx >= s.next {
+			break
+		}
+		if _, err := w.Write(s.records[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpOnPanic writes the retained records to w if the calling goroutine is panicking, then resumes
+// the panic. It must be deferred directly:
+//
+//	defer recorder.DumpOnPanic(os.Stdout)
+func (f *FlightRecorder) DumpOnPanic(w io.Writer) {
+	if r := recover(); r != nil {
+		_ = f.DumpTo(w)
+		panic(r)
+	}
+}
+
+// Enabled always reports true, as the FlightRecorder retains records of every level.
+func (f *FlightRecorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (f *FlightRecorder) Handle(ctx context.Context, record slog.Record) error {
+	_ = f.encoder.Handle(ctx, record.Clone())
+
+	if record.Level >= slog.LevelError+fatalLevelErrorOffset {
+		f.state.mu.Lock()
+		if f.state.fatal != nil {
+			_ = f.state.dumpTo(f.state.fatal)
+		}
+		f.state.mu.Unlock()
+	}
+
+	if !f.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return f.next.Handle(ctx, record)
+}
+
+func (f *FlightRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &FlightRecorder{
+		next:    f.next.WithAttrs(attrs),
+		encoder: f.encoder.WithAttrs(attrs),
+		state:   f.state,
+	}
+}
+
+func (f *FlightRecorder) WithGroup(name string) slog.Handler {
+	return &FlightRecorder{
+		next:    f.next.WithGroup(name),
+		encoder: f.encoder.WithGroup(name),
+		state:   f.state,
+	}
+}
+
+var _ slog.Handler = (*FlightRecorder)(nil)
@@ -0,0 +1,65 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	t.Run("DumpTo", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		recorder := sloglambda.NewFlightRecorder(sloglambda.NewHandler(output, sloglambda.WithJSON()), 2, sloglambda.WithJSON())
+		logger := slog.New(recorder).With("component", "test")
+
+		logger.Debug("first")
+		logger.Debug("second")
+		logger.Info("third")
+
+		assert.NotContains(t, output.String(), `"msg":"second"`, "the next handler filters debug records")
+		assert.Contains(t, output.String(), `"msg":"third"`)
+
+		dump := new(bytes.Buffer)
+		require.NoError(t, recorder.DumpTo(dump))
+
+		lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"msg":"second"`)
+		assert.Contains(t, lines[0], `"component":"test"`)
+		assert.Contains(t, lines[1], `"msg":"third"`)
+	})
+
+	t.Run("DumpOnFatal", func(t *testing.T) {
+		dump := new(bytes.Buffer)
+		recorder := sloglambda.NewFlightRecorder(sloglambda.NewHandler(new(bytes.Buffer)), 10, sloglambda.WithJSON()).DumpOnFatal(dump)
+		logger := slog.New(recorder)
+
+		logger.Debug("context")
+		assert.Empty(t, dump.String())
+
+		logger.Log(context.Background(), slog.LevelError+4, "fatal")
+		assert.Contains(t, dump.String(), `"msg":"context"`)
+		assert.Contains(t, dump.String(), `"msg":"fatal"`)
+	})
+
+	t.Run("DumpOnPanic", func(t *testing.T) {
+		dump := new(bytes.Buffer)
+		recorder := sloglambda.NewFlightRecorder(sloglambda.NewHandler(new(bytes.Buffer)), 10, sloglambda.WithJSON())
+		logger := slog.New(recorder)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			defer recorder.DumpOnPanic(dump)
+
+			logger.Debug("before panic")
+			panic("boom")
+		})
+
+		assert.Contains(t, dump.String(), `"msg":"before panic"`)
+	})
+}
@@ -0,0 +1,119 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// AdaptiveLevel is a slog.Leveler that lowers the minimum level while recent invocations have
+// logged errors, and restores it once they are healthy again.
+//
+// The outcome of the most recent invocations is kept in a fixed size sliding window. While any
+// invocation in the window logged a record at ERROR or above, Level returns the elevated level,
+// otherwise it returns the healthy level.
+type AdaptiveLevel struct {
+	healthy  slog.Level
+	elevated slog.Level
+
+	mu      sync.Mutex
+	window  []bool
+	next    int
+	errored int
+}
+
+// NewAdaptiveLevel creates an AdaptiveLevel using a sliding window of the given number of
+// invocations. A window smaller than one is treated as one.
+func NewAdaptiveLevel(healthy, elevated slog.Level, window int) *AdaptiveLevel {
+	if window < 1 {
+		window = 1
+	}
+	return &AdaptiveLevel{
+		healthy:  healthy,
+		elevated: elevated,
+		window:   make([]bool, window),
+	}
+}
+
+// Level implements slog.Leveler.
+func (a *AdaptiveLevel) Level() slog.Level {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.errored > 0 {
+		return a.elevated
+	}
+	return a.healthy
+}
+
+// Observe records the outcome of a finished invocation.
+func (a *AdaptiveLevel) Observe(errored bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.window[a.next] {
+		a.errored--
+	}
+	if errored {
+		a.errored++
+	}
+	a.window[a.next] = errored
+	a.next = (a.next + 1) % len(a.window)
+}
+
+var _ slog.Leveler = (*AdaptiveLevel)(nil)
+
+// WithAdaptiveLevel configures the Handler to use the given AdaptiveLevel as its log level.
+//
+// The Handler reports the outcome of each invocation to the AdaptiveLevel when the invocation
+// ends (see Handler.EndInvocation).
+func WithAdaptiveLevel(level *AdaptiveLevel) Option {
+	return func(h *Handler) {
+		if level == nil {
+			h.invalidOption("WithAdaptiveLevel: nil level")
+			return
+		}
+		h.level = level
+		h.levelSource = settingOption
+		h.adaptive = level
+	}
+}
+
+type messageSampling struct {
+	first      int
+	thereafter int
+}
+
+type messageCount struct {
+	seen    int
+	written int
+}
+
+// sample reports whether the occurrence of the message should be written, updating its count.
+func (s *messageSampling) sample(count *messageCount) bool {
+	count.seen++
+
+	if count.seen <= s.first || (s.thereafter > 0 && (count.seen-s.first)%s.thereafter == 0) {
+		count.written++
+		return true
+	}
+	return false
+}
+
+// WithMessageSampling configures the Handler to sample records by their message within each
+// invocation.
+//
+// The first occurrences of a message are always written, after that only one in every thereafter
+// occurrences is written. A thereafter of zero or less drops every occurrence after the first.
+// WARN and above are never sampled. When the invocation ends (see Handler.EndInvocation) a summary
+// record with the total count is written for each message that was sampled.
+func WithMessageSampling(first int, thereafter int) Option {
+	return func(h *Handler) {
+		if first < 0 || thereafter < 0 {
+			h.invalidOption("WithMessageSampling: negative count")
+		}
+		h.messageSampling = &messageSampling{
+			first:      first,
+			thereafter: thereafter,
+		}
+	}
+}
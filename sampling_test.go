@@ -0,0 +1,79 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveLevel(t *testing.T) {
+	level := sloglambda.NewAdaptiveLevel(slog.LevelInfo, slog.LevelDebug, 2)
+
+	assert.Equal(t, slog.LevelInfo, level.Level())
+
+	level.Observe(true)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	level.Observe(false)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "the errored invocation is still in the window")
+
+	level.Observe(false)
+	assert.Equal(t, slog.LevelInfo, level.Level())
+}
+
+func TestWithAdaptiveLevel(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithAdaptiveLevel(
+		sloglambda.NewAdaptiveLevel(slog.LevelInfo, slog.LevelDebug, 5),
+	))
+	logger := slog.New(handler)
+
+	logger.DebugContext(ctx, "before")
+	logger.ErrorContext(ctx, "failed")
+	require.NoError(t, handler.EndInvocation(ctx))
+
+	logger.DebugContext(ctx, "after")
+
+	assert.NotContains(t, buffer.String(), `"msg":"before"`)
+	assert.Contains(t, buffer.String(), `"msg":"after"`)
+}
+
+func TestWithMessageSampling(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "abc-123",
+	})
+
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithMessageSampling(2, 3))
+	logger := slog.New(handler)
+
+	for i := 1; i <= 8; i++ {
+		logger.InfoContext(ctx, "item", "i", i)
+	}
+	logger.InfoContext(ctx, "other")
+
+	require.NoError(t, handler.EndInvocation(ctx))
+
+	output := buffer.String()
+	for _, i := range []string{"1", "2", "5", "8"} {
+		assert.Contains(t, output, `"i":`+i+`,`)
+	}
+	for _, i := range []string{"3", "4", "6", "7"} {
+		assert.NotContains(t, output, `"i":`+i+`,`)
+	}
+	assert.Contains(t, output, `"msg":"other"`)
+	assert.Contains(t, output, `"msg":"message sampled"`)
+	assert.Contains(t, output, `"sampled":"item"`)
+	assert.Contains(t, output, `"total":8`)
+	assert.Contains(t, output, `"written":4`)
+}
@@ -0,0 +1,163 @@
+package sloglambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldType is the JSON type of a field in a record.
+type FieldType string
+
+const (
+	FieldString  FieldType = "string"
+	FieldNumber  FieldType = "number"
+	FieldInteger FieldType = "integer"
+	FieldBoolean FieldType = "boolean"
+	FieldObject  FieldType = "object"
+	FieldArray   FieldType = "array"
+)
+
+// Schema describes the fields records written by a Handler must contain.
+//
+// Fields are identified by their dot separated path in the written record, for example "msg",
+// "record.requestId", or "http.status".
+type Schema struct {
+	// Required lists the fields every record must contain.
+	Required []string
+	// Types maps fields to the type their value must have when present.
+	Types map[string]FieldType
+	// Panic causes violations to panic instead of being reported to the error handler. It is
+	// intended for tests.
+	Panic bool
+}
+
+// ErrSchemaViolation is wrapped by the errors reported when a record does not match the Schema.
+var ErrSchemaViolation = errors.New("schema violation")
+
+// WithSchema configures the Handler to validate every record against the schema before it is
+// written, reporting violations to the error handler (see WithErrorHandler). Records that violate
+// the schema are still written.
+//
+// Validation is intended for development, to catch logging contract drift before deploying.
+func WithSchema(schema *Schema) Option {
+	return func(h *Handler) {
+		for path, fieldType := range schema.Types {
+			switch fieldType {
+			case FieldString, FieldNumber, FieldInteger, FieldBoolean, FieldObject, FieldArray:
+			default:
+				h.invalidOption("WithSchema: unknown type %q for %q", fieldType, path)
+			}
+		}
+		h.schema = schema
+	}
+}
+
+// WithErrorHandler configures a function that is called with errors the Handler encounters that
+// cannot be returned to the caller, such as schema violations, records that fail to encode, and
+// errors writing or flushing the sink, including records an HTTP sink's endpoint rejected.
+func WithErrorHandler(fn func(ctx context.Context, err error)) Option {
+	return func(h *Handler) {
+		h.errorHandler = fn
+	}
+}
+
+func (h *Handler) reportError(ctx context.Context, err error) {
+	if h.errorHandler != nil && err != nil {
+		h.errorHandler(ctx, err)
+	}
+}
+
+// validate returns an error describing every way the record violates the schema.
+func (s *Schema) validate(record logRecord) error {
+	var errs []error
+
+	for _, path := range s.Required {
+		if _, ok := record.lookup(path); !ok {
+			errs = append(errs, fmt.Errorf("%w: %q is required", ErrSchemaViolation, path))
+		}
+	}
+
+	paths := make([]string, 0, len(s.Types))
+	for path := range s.Types {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		value, ok := record.lookup(path)
+		if !ok {
+			continue
+		}
+		if expected, actual := s.Types[path], fieldTypeOf(value); !fieldTypeMatches(expected, actual) {
+			errs = append(errs, fmt.Errorf("%w: %q must be %s, got %s", ErrSchemaViolation, path, expected, actual))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// lookup returns the value at the dot separated path.
+func (r logRecord) lookup(path string) (any, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+
+	value, ok := r[key]
+	if !ok || !nested {
+		return value, ok
+	}
+
+	sub, ok := value.(logRecord)
+	if !ok {
+		return nil, false
+	}
+	return sub.lookup(rest)
+}
+
+func fieldTypeOf(value any) FieldType {
+	switch v := value.(type) {
+	case string:
+		return FieldString
+	case bool:
+		return FieldBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return FieldInteger
+	case float32, float64:
+		return FieldNumber
+	case logRecord, map[string]any:
+		return FieldObject
+	case json.RawMessage:
+		var decoded any
+		if json.Unmarshal(v, &decoded) == nil {
+			return fieldTypeOf(decoded)
+		}
+		return FieldString
+	case nil:
+		return "null"
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil || len(b) == 0 {
+		return FieldString
+	}
+	switch b[0] {
+	case '[':
+		return FieldArray
+	case '{':
+		return FieldObject
+	case '"':
+		return FieldString
+	case 't', 'f':
+		return FieldBoolean
+	case 'n':
+		return "null"
+	default:
+		return FieldNumber
+	}
+}
+
+func fieldTypeMatches(expected, actual FieldType) bool {
+	return expected == actual || (expected == FieldNumber && actual == FieldInteger)
+}
@@ -0,0 +1,77 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSchema(t *testing.T) {
+	schema := &sloglambda.Schema{
+		Required: []string{"msg", "orderId"},
+		Types: map[string]sloglambda.FieldType{
+			"orderId":     sloglambda.FieldString,
+			"order.total": sloglambda.FieldNumber,
+			"order.items": sloglambda.FieldArray,
+		},
+	}
+
+	t.Run("when the record matches", func(t *testing.T) {
+		var errs []error
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSchema(schema), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			errs = append(errs, err)
+		})))
+
+		logger.Info(t.Name(), "orderId", "o-1", slog.Group("order", "total", 10, "items", []string{"a"}))
+
+		assert.Empty(t, errs)
+		assert.NotEmpty(t, buffer.String())
+	})
+
+	t.Run("when the record violates the schema", func(t *testing.T) {
+		var errs []error
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSchema(schema), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+			errs = append(errs, err)
+		})))
+
+		logger.Info(t.Name(), slog.Group("order", "total", "ten"))
+
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], sloglambda.ErrSchemaViolation)
+		assert.ErrorContains(t, errs[0], `"orderId" is required`)
+		assert.ErrorContains(t, errs[0], `"order.total" must be number, got string`)
+		assert.NotEmpty(t, buffer.String(), "the record is still written")
+	})
+
+	t.Run("when configured to panic", func(t *testing.T) {
+		logger := slog.New(sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithJSON(), sloglambda.WithSchema(&sloglambda.Schema{
+			Required: []string{"orderId"},
+			Panic:    true,
+		})))
+
+		assert.Panics(t, func() {
+			logger.Info(t.Name())
+		})
+	})
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var errs []error
+
+	logger := slog.New(sloglambda.NewHandler(new(bytes.Buffer), sloglambda.WithJSON(), sloglambda.WithErrorHandler(func(_ context.Context, err error) {
+		errs = append(errs, err)
+	})))
+
+	logger.Info(t.Name(), "value", func() {})
+
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,33 @@
+package sloglambda
+
+import (
+	"context"
+	"slices"
+)
+
+type scopeKey struct{}
+
+// Scope returns a copy of ctx whose records are nested under a group with the given name.
+//
+// Scopes nest: a scope opened with a context returned by Scope is nested under its parent's group.
+// The groups follow any added with Logger.WithGroup, and, as with WithGroup, a scope is omitted
+// from records that have no attributes of their own. Records logged with the parent context are not
+// affected, so a scope ends when the function that opened it stops using the derived context.
+//
+// Records are only nested when they are logged with the derived context, for example with
+// Logger.InfoContext. An empty name returns ctx unchanged.
+func Scope(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeKey{}, append(slices.Clip(scopesFromContext(ctx)), name))
+}
+
+// scopesFromContext returns the names of the scopes opened with ctx, outermost first.
+func scopesFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	scopes, _ := ctx.Value(scopeKey{}).([]string)
+	return scopes
+}
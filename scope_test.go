@@ -0,0 +1,51 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScope(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(sloglambda.NewHandler(&buf, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithType("")))
+
+	ctx := sloglambda.Scope(context.Background(), "checkout")
+
+	t.Run("nests attributes under the scope", func(t *testing.T) {
+		buf.Reset()
+		logger.InfoContext(ctx, "started", "cart", 42)
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"started","record":{"functionName":"test-function","version":"$LATEST"},"checkout":{"cart":42}}`, buf.String())
+	})
+
+	t.Run("nested scopes", func(t *testing.T) {
+		buf.Reset()
+		logger.With("id", 1).WithGroup("req").InfoContext(sloglambda.Scope(ctx, "payment"), "charged", "amount", 10)
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"charged","record":{"functionName":"test-function","version":"$LATEST"},"id":1,"req":{"checkout":{"payment":{"amount":10}}}}`, buf.String())
+	})
+
+	t.Run("parent context is not nested", func(t *testing.T) {
+		buf.Reset()
+		_ = sloglambda.Scope(ctx, "payment")
+		logger.InfoContext(context.Background(), "done", "ok", true)
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"done","record":{"functionName":"test-function","version":"$LATEST"},"ok":true}`, buf.String())
+	})
+
+	t.Run("omitted without attributes", func(t *testing.T) {
+		buf.Reset()
+		logger.InfoContext(ctx, "empty")
+
+		assert.JSONEq(t, `{"level":"INFO","msg":"empty","record":{"functionName":"test-function","version":"$LATEST"}}`, buf.String())
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		assert.Equal(t, ctx, sloglambda.Scope(ctx, ""))
+	})
+}
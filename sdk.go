@@ -0,0 +1,65 @@
+package sloglambda
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var kSDKClassification = "classification"
+
+// SDKLogger adapts a slog.Handler to the logging.Logger interface used by aws-sdk-go-v2.
+//
+// The type parameter is the SDK's classification type, which keeps this package free of a
+// dependency on the SDK:
+//
+//	cfg.Logger = sloglambda.NewSDKLogger[logging.Classification](handler)
+//
+// WARN classifications are logged at slog.LevelWarn, DEBUG at slog.LevelDebug, and anything else at
+// slog.LevelInfo. To log every API call a client makes, see the sloglambdasdk module.
+type SDKLogger[C ~string] struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+// NewSDKLogger creates an SDKLogger writing to the given handler.
+func NewSDKLogger[C ~string](h slog.Handler) *SDKLogger[C] {
+	return &SDKLogger[C]{
+		handler: h,
+		ctx:     context.Background(),
+	}
+}
+
+// Context returns a copy of the logger that logs records with the given context, so they are
+// associated with the invocation it belongs to.
+func (l *SDKLogger[C]) Context(ctx context.Context) *SDKLogger[C] {
+	c := *l
+	c.ctx = ctx
+	return &c
+}
+
+// Logf implements the aws-sdk-go-v2 logging.Logger interface.
+func (l *SDKLogger[C]) Logf(classification C, format string, v ...any) {
+	level := sdkClassificationLevel(string(classification))
+	if !l.handler.Enabled(l.ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, v...), 0)
+	record.AddAttrs(slog.String(kSDKClassification, string(classification)))
+
+	_ = l.handler.Handle(l.ctx, record)
+}
+
+func sdkClassificationLevel(classification string) slog.Level {
+	switch strings.ToUpper(classification) {
+	case "WARN":
+		return slog.LevelWarn
+	case "DEBUG":
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
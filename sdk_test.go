@@ -0,0 +1,39 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+// classification mirrors the aws-sdk-go-v2 logging.Classification type.
+type classification string
+
+type sdkLogger interface {
+	Logf(classification classification, format string, v ...any)
+}
+
+func TestSDKLogger(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	var logger sdkLogger = sloglambda.NewSDKLogger[classification](sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	t.Run("WARN", func(t *testing.T) {
+		t.Cleanup(buffer.Reset)
+
+		logger.Logf("WARN", "retrying request %d", 2)
+
+		assert.Contains(t, buffer.String(), `"level":"WARN"`)
+		assert.Contains(t, buffer.String(), `"msg":"retrying request 2"`)
+		assert.Contains(t, buffer.String(), `"classification":"WARN"`)
+	})
+
+	t.Run("DEBUG", func(t *testing.T) {
+		t.Cleanup(buffer.Reset)
+
+		logger.Logf("DEBUG", "request sent")
+
+		assert.Empty(t, buffer.String(), "the handler level filters debug records")
+	})
+}
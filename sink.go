@@ -0,0 +1,103 @@
+package sloglambda
+
+import (
+	"context"
+	"io"
+)
+
+// Sink is a destination for encoded records.
+//
+// Each call to Write receives exactly one record, terminated by a newline. The Handler calls Flush
+// when an invocation ends (see Handler.EndInvocation), and Flush followed by Close when it is closed
+// (see Handler.Close), so a Sink that buffers or batches records can deliver them before the
+// execution environment is frozen.
+//
+// Unless the Handler is configured with WithConcurrentWriter, calls to Write are serialized.
+type Sink interface {
+	Write(ctx context.Context, p []byte) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// WithSink configures the Handler to write log messages to s.
+func WithSink(s Sink) Option {
+	return func(h *Handler) {
+		if s == nil {
+			h.invalidOption("WithSink: nil sink")
+			return
+		}
+		h.sink = s
+	}
+}
+
+// NewSinkHandler creates a new Handler that writes log messages to the given Sink.
+//
+// It is configured the same way as a Handler created with NewHandler.
+func NewSinkHandler(s Sink, options ...Option) *Handler {
+	return NewHandler(nil, append([]Option{WithSink(s)}, options...)...)
+}
+
+// WriterSink returns a Sink that writes records to w.
+//
+// Flush calls the Flush method of w if it has one, as BatchWriter and the HTTP writers do. Close
+// flushes w but does not close it, so the sink can wrap a writer the Handler does not own, such as
+// os.Stdout. Use WriteCloserSink to close the writer along with the Handler.
+func WriterSink(w io.Writer) Sink {
+	return writerSink{w: w}
+}
+
+// WriteCloserSink returns a Sink that writes records to w and closes it when the sink is closed.
+func WriteCloserSink(w io.WriteCloser) Sink {
+	return writeCloserSink{writerSink{w: w}}
+}
+
+type writerSink struct {
+	w io.Writer
+}
+
+func (s writerSink) Write(ctx context.Context, p []byte) error {
+	if w, ok := s.w.(contextWriter); ok {
+		return w.writeContext(ctx, p)
+	}
+	_, err := s.w.Write(p)
+	return err
+}
+
+func (s writerSink) Flush(ctx context.Context) error {
+	if f, ok := s.w.(contextFlusher); ok {
+		return f.flushContext(ctx)
+	}
+	if f, ok := s.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (s writerSink) Close() error {
+	return s.Flush(context.Background())
+}
+
+// contextWriter is implemented by writers that use the context a record was logged with, such as
+// OTLPWriter.
+type contextWriter interface {
+	writeContext(ctx context.Context, p []byte) error
+}
+
+// contextFlusher is implemented by writers that use the context of the invocation being ended
+// when they are flushed, such as OTLPWriter.
+type contextFlusher interface {
+	flushContext(ctx context.Context) error
+}
+
+type writeCloserSink struct {
+	writerSink
+}
+
+func (s writeCloserSink) Close() error {
+	return s.w.(io.Closer).Close()
+}
+
+var (
+	_ Sink = writerSink{}
+	_ Sink = writeCloserSink{}
+)
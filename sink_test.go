@@ -0,0 +1,128 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkKey struct{}
+
+type recordingSink struct {
+	records []string
+	values  []any
+	flushes int
+	closed  bool
+	err     error
+}
+
+func (s *recordingSink) Write(ctx context.Context, p []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, string(p))
+	s.values = append(s.values, ctx.Value(sinkKey{}))
+	return nil
+}
+
+func (s *recordingSink) Flush(context.Context) error {
+	s.flushes++
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	t.Run("writes each record with the logging context", func(t *testing.T) {
+		sink := new(recordingSink)
+		logger := slog.New(sloglambda.NewSinkHandler(sink, sloglambda.WithoutTime()))
+
+		ctx := context.WithValue(context.Background(), sinkKey{}, "value")
+		logger.InfoContext(ctx, "Hello, world!")
+
+		require.Len(t, sink.records, 1)
+		assert.Equal(t, `{"level":"INFO","msg":"Hello, world!","record":{"functionName":"test-function","version":"$LATEST"},"type":"app.log"}`+"\n", sink.records[0])
+		assert.Equal(t, []any{"value"}, sink.values)
+	})
+
+	t.Run("flushes at the end of the invocation", func(t *testing.T) {
+		sink := new(recordingSink)
+		handler := sloglambda.NewSinkHandler(sink)
+
+		require.NoError(t, handler.EndInvocation(context.Background()))
+		assert.Equal(t, 1, sink.flushes)
+		assert.False(t, sink.closed)
+	})
+
+	t.Run("close flushes and closes the sink", func(t *testing.T) {
+		sink := new(recordingSink)
+		handler := sloglambda.NewSinkHandler(sink)
+
+		require.NoError(t, handler.Close())
+		assert.Equal(t, 1, sink.flushes)
+		assert.True(t, sink.closed)
+	})
+
+	t.Run("write errors are not counted", func(t *testing.T) {
+		sink := &recordingSink{err: errors.New("unavailable")}
+		handler := sloglambda.NewSinkHandler(sink)
+
+		slog.New(handler).Info("Hello, world!")
+		assert.Zero(t, handler.Stats().BytesWritten)
+	})
+
+	t.Run("nil sink", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(nil, sloglambda.WithSink(nil))
+		assert.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
+
+func TestWriterSink(t *testing.T) {
+	t.Run("does not close the writer", func(t *testing.T) {
+		w := new(closeRecorder)
+		sink := sloglambda.WriterSink(w)
+
+		require.NoError(t, sink.Write(context.Background(), []byte("record\n")))
+		require.NoError(t, sink.Close())
+		assert.Equal(t, "record\n", w.String())
+		assert.False(t, w.closed)
+	})
+
+	t.Run("flushes a buffering writer", func(t *testing.T) {
+		out := new(lineRecorder)
+		sink := sloglambda.WriterSink(sloglambda.NewBatchWriter(out, 1024, 0))
+
+		require.NoError(t, sink.Write(context.Background(), []byte("record\n")))
+		assert.Empty(t, out.writes)
+
+		require.NoError(t, sink.Flush(context.Background()))
+		assert.Equal(t, []string{"record\n"}, out.writes)
+	})
+}
+
+func TestWriteCloserSink(t *testing.T) {
+	w := new(closeRecorder)
+	sink := sloglambda.WriteCloserSink(w)
+
+	require.NoError(t, sink.Close())
+	assert.True(t, w.closed)
+}
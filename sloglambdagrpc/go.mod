@@ -0,0 +1,11 @@
+module github.com/maddiesch/slog-lambda/sloglambdagrpc
+
+go 1.23.0
+
+require (
+	github.com/maddiesch/slog-lambda v0.0.0
+	github.com/stretchr/testify v1.8.0
+	google.golang.org/grpc v1.71.0
+)
+
+replace github.com/maddiesch/slog-lambda => ../
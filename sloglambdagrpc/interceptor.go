@@ -0,0 +1,125 @@
+// Package sloglambdagrpc provides gRPC server interceptors that log calls through a sloglambda
+// Handler.
+//
+// It is a separate module so that sloglambda itself does not depend on gRPC.
+package sloglambdagrpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	kGRPCGroup    = "grpc"
+	kGRPCMethod   = "method"
+	kGRPCPeer     = "peer"
+	kGRPCDeadline = "deadline"
+	kGRPCCode     = "code"
+	kGRPCDuration = "durationMs"
+	kGRPCError    = "error"
+)
+
+// CallLogType is the "type" of the records written when a call completes.
+const CallLogType = "grpc.call"
+
+// UnaryServerInterceptor returns an interceptor that stores a child of logger carrying the call's
+// "method", "peer" address, and "deadline" in a "grpc" group in the call's context, where it can be
+// retrieved with sloglambda.LoggerFromContext, and logs the call's status "code" and "durationMs"
+// once it completes (see CodeLevel).
+//
+// If logger is nil, the logger from the call's context is used.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, logger, attrs := withCallLogger(ctx, logger, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+
+		logCompletion(ctx, logger, attrs, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that does the same as UnaryServerInterceptor for
+// streaming calls, logging once the stream completes.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, logger, attrs := withCallLogger(ss.Context(), logger, info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		logCompletion(ctx, logger, attrs, start, err)
+		return err
+	}
+}
+
+// CodeLevel returns the level a call completing with the given code is logged at: codes caused by
+// the client are logged at WARN, codes caused by the server at ERROR, and OK at INFO.
+func CodeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.OutOfRange:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// withCallLogger returns a copy of ctx carrying the child logger for the call, along with the
+// logger it is a child of and the attributes it adds.
+func withCallLogger(ctx context.Context, logger *slog.Logger, method string) (context.Context, *slog.Logger, []any) {
+	if logger == nil {
+		logger = sloglambda.LoggerFromContext(ctx)
+	}
+
+	attrs := []any{slog.String(kGRPCMethod, method)}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String(kGRPCPeer, p.Addr.String()))
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		attrs = append(attrs, slog.Time(kGRPCDeadline, deadline))
+	}
+
+	return sloglambda.ContextWithLogger(ctx, logger.With(slog.Group(kGRPCGroup, attrs...))), logger, attrs
+}
+
+// logCompletion logs the completion of a call with logger, adding the call's status to its
+// attributes so they are written in a single "grpc" group.
+func logCompletion(ctx context.Context, logger *slog.Logger, attrs []any, start time.Time, err error) {
+	code := status.Code(err)
+	level := CodeLevel(code)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs = append(attrs[:len(attrs):len(attrs)],
+		slog.String(kGRPCCode, code.String()),
+		slog.Int64(kGRPCDuration, time.Since(start).Milliseconds()),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String(kGRPCError, status.Convert(err).Message()))
+	}
+
+	logger.LogAttrs(ctx, level, "call completed", sloglambda.Type(CallLogType), slog.Group(kGRPCGroup, attrs...))
+}
+
+// serverStream replaces the context of a stream with the one carrying the call's logger.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,122 @@
+package sloglambdagrpc_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdagrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext() context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234},
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Run("logs through the call logger", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+		interceptor := sloglambdagrpc.UnaryServerInterceptor(logger)
+
+		info := &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+		resp, err := interceptor(peerContext(), "request", info, func(ctx context.Context, req any) (any, error) {
+			sloglambda.LoggerFromContext(ctx).InfoContext(ctx, "handling")
+			return "response", nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "response", resp)
+
+		lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		assert.Contains(t, string(lines[0]), `"msg":"handling"`)
+		assert.Contains(t, string(lines[0]), `"method":"/orders.Orders/Get"`)
+		assert.Contains(t, string(lines[0]), `"peer":"192.0.2.1:1234"`)
+
+		assert.Contains(t, string(lines[1]), `"level":"INFO"`)
+		assert.Contains(t, string(lines[1]), `"type":"grpc.call"`)
+		assert.Contains(t, string(lines[1]), `"method":"/orders.Orders/Get"`)
+		assert.Contains(t, string(lines[1]), `"code":"OK"`)
+	})
+
+	t.Run("logs the deadline", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+		interceptor := sloglambdagrpc.UnaryServerInterceptor(logger)
+
+		ctx, cancel := context.WithTimeout(peerContext(), time.Minute)
+		defer cancel()
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"deadline":`)
+	})
+
+	t.Run("logs the status of failed calls", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+		interceptor := sloglambdagrpc.UnaryServerInterceptor(logger)
+
+		_, err := interceptor(peerContext(), nil, &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}, func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.NotFound, "no such order")
+		})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Contains(t, buffer.String(), `"level":"WARN"`)
+		assert.Contains(t, buffer.String(), `"code":"NotFound"`)
+		assert.Contains(t, buffer.String(), `"error":"no such order"`)
+	})
+}
+
+// testStream is a grpc.ServerStream that only has a context.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+	interceptor := sloglambdagrpc.StreamServerInterceptor(logger)
+
+	info := &grpc.StreamServerInfo{FullMethod: "/orders.Orders/Watch", IsServerStream: true}
+	err := interceptor(nil, &testStream{ctx: peerContext()}, info, func(srv any, stream grpc.ServerStream) error {
+		sloglambda.LoggerFromContext(stream.Context()).Info("streaming")
+		return status.Error(codes.Internal, "stream broke")
+	})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	assert.Contains(t, string(lines[0]), `"method":"/orders.Orders/Watch"`)
+	assert.Contains(t, string(lines[1]), `"level":"ERROR"`)
+	assert.Contains(t, string(lines[1]), `"code":"Internal"`)
+}
+
+func TestCodeLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, sloglambdagrpc.CodeLevel(codes.OK))
+	assert.Equal(t, slog.LevelWarn, sloglambdagrpc.CodeLevel(codes.PermissionDenied))
+	assert.Equal(t, slog.LevelError, sloglambdagrpc.CodeLevel(codes.Unavailable))
+}
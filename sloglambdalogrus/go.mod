@@ -0,0 +1,11 @@
+module github.com/maddiesch/slog-lambda/sloglambdalogrus
+
+go 1.23.0
+
+require (
+	github.com/maddiesch/slog-lambda v0.0.0
+	github.com/sirupsen/logrus v1.9.3
+	github.com/stretchr/testify v1.8.0
+)
+
+replace github.com/maddiesch/slog-lambda => ../
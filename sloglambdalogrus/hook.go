@@ -0,0 +1,100 @@
+// Package sloglambdalogrus routes logrus entries through a sloglambda Handler, so code that still
+// logs with logrus gets the same Lambda aware output as code that logs with slog.
+//
+// It is a separate module so that sloglambda itself does not depend on logrus.
+package sloglambdalogrus
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	levelTrace, _ = sloglambda.ParseLevel("TRACE")
+	levelFatal, _ = sloglambda.ParseLevel("FATAL")
+)
+
+// Hook is a logrus.Hook that writes every entry to a slog.Handler.
+//
+// The logger the hook is added to should discard its own output (see NewLogger), otherwise every
+// entry is written twice.
+type Hook struct {
+	handler slog.Handler
+}
+
+// NewHook creates a Hook writing to the given handler.
+func NewHook(h slog.Handler) *Hook {
+	return &Hook{handler: h}
+}
+
+// NewLogger creates a logrus.Logger that writes only through the given handler. The logger's level
+// is set to logrus.TraceLevel, so which entries are written is decided by the handler's level.
+func NewLogger(h slog.Handler) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(NewHook(h))
+	return logger
+}
+
+// Levels implements logrus.Hook, firing for every level.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook, writing the entry as a record at the level mapped by Level, with its
+// fields as attributes in sorted order. Errors, such as the one added by Entry.WithError, are
+// written as their message.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := Level(entry.Level)
+	if !h.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	var pc uintptr
+	if entry.Caller != nil {
+		pc = entry.Caller.PC
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, pc)
+	for _, key := range slices.Sorted(maps.Keys(entry.Data)) {
+		switch value := entry.Data[key].(type) {
+		case error:
+			record.AddAttrs(slog.String(key, value.Error()))
+		default:
+			record.AddAttrs(slog.Any(key, value))
+		}
+	}
+
+	return h.handler.Handle(ctx, record)
+}
+
+// Level returns the slog level a logrus level is written at. The trace level is written as TRACE,
+// and the fatal and panic levels as FATAL.
+func Level(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.TraceLevel:
+		return levelTrace
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	default:
+		return levelFatal
+	}
+}
@@ -0,0 +1,63 @@
+package sloglambdalogrus_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdalogrus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Run("writes entries through the handler", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := sloglambdalogrus.NewLogger(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		logger.WithField("user", "alice").WithError(errors.New("boom")).Warn("careful")
+
+		assert.Contains(t, buffer.String(), `"level":"WARN"`)
+		assert.Contains(t, buffer.String(), `"msg":"careful"`)
+		assert.Contains(t, buffer.String(), `"user":"alice"`)
+		assert.Contains(t, buffer.String(), `"error":"boom"`)
+	})
+
+	t.Run("uses the level of the handler", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := sloglambdalogrus.NewLogger(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelInfo)))
+
+		logger.Debug("hidden")
+		assert.Empty(t, buffer.String())
+
+		logger.Info("shown")
+		assert.Contains(t, buffer.String(), `"msg":"shown"`)
+	})
+}
+
+func TestHook(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	output := new(bytes.Buffer)
+
+	logger := logrus.New()
+	logger.SetOutput(output)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(sloglambdalogrus.NewHook(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug-4))))
+
+	logger.Trace("tracing")
+
+	assert.Contains(t, buffer.String(), `"level":"TRACE"`)
+	assert.NotEmpty(t, output.String())
+}
+
+func TestLevel(t *testing.T) {
+	trace, _ := sloglambda.ParseLevel("TRACE")
+	fatal, _ := sloglambda.ParseLevel("FATAL")
+
+	assert.Equal(t, trace, sloglambdalogrus.Level(logrus.TraceLevel))
+	assert.Equal(t, slog.LevelWarn, sloglambdalogrus.Level(logrus.WarnLevel))
+	assert.Equal(t, fatal, sloglambdalogrus.Level(logrus.FatalLevel))
+	assert.Equal(t, fatal, sloglambdalogrus.Level(logrus.PanicLevel))
+}
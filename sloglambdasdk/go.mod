@@ -0,0 +1,12 @@
+module github.com/maddiesch/slog-lambda/sloglambdasdk
+
+go 1.23.0
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.36.3
+	github.com/aws/smithy-go v1.22.2
+	github.com/maddiesch/slog-lambda v0.0.0
+	github.com/stretchr/testify v1.8.0
+)
+
+replace github.com/maddiesch/slog-lambda => ../
@@ -0,0 +1,147 @@
+// Package sloglambdasdk logs the AWS API calls made with aws-sdk-go-v2 through a sloglambda
+// Handler.
+//
+// It is a separate module so that sloglambda itself does not depend on the AWS SDK.
+package sloglambdasdk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	sloglambda "github.com/maddiesch/slog-lambda"
+)
+
+var (
+	kAWSGroup     = "aws"
+	kAWSService   = "service"
+	kAWSOperation = "operation"
+	kAWSDuration  = "durationMs"
+	kAWSStatus    = "status"
+	kAWSRequestID = "requestId"
+	kAWSRetries   = "retries"
+	kAWSError     = "error"
+)
+
+// CallLogType is the "type" of the records written for AWS API calls.
+const CallLogType = "aws.call"
+
+// MiddlewareID is the ID of the call logging middleware in the SDK's middleware stack.
+const MiddlewareID = "sloglambda.CallLogging"
+
+// Option configures the call logging middleware.
+type Option func(*callLogger)
+
+// WithLevels configures the levels calls are logged at: level for calls that succeed, and
+// errorLevel for calls that fail. The defaults are slog.LevelDebug and slog.LevelError.
+func WithLevels(level, errorLevel slog.Level) Option {
+	return func(l *callLogger) {
+		l.level = level
+		l.errorLevel = errorLevel
+	}
+}
+
+// CallLogging returns an SDK API option that logs every AWS API call made by a client with the
+// "service" and "operation" called, its "durationMs" including retries, the HTTP "status" of the
+// last attempt, the AWS "requestId", the number of "retries", and the "error" of a failed call, in
+// an "aws" group:
+//
+//	cfg.APIOptions = append(cfg.APIOptions, sloglambdasdk.CallLogging(nil))
+//
+// If logger is nil, calls are logged with the logger of the context the call is made with (see
+// sloglambda.LoggerFromContext), so they are associated with the invocation making them.
+func CallLogging(logger *slog.Logger, options ...Option) func(*middleware.Stack) error {
+	l := &callLogger{
+		logger:     logger,
+		level:      slog.LevelDebug,
+		errorLevel: slog.LevelError,
+	}
+	for _, option := range options {
+		option(l)
+	}
+
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(l, middleware.After)
+	}
+}
+
+// callLogger is an initialize step middleware, so it times the call across all of its attempts.
+type callLogger struct {
+	logger     *slog.Logger
+	level      slog.Level
+	errorLevel slog.Level
+}
+
+func (l *callLogger) ID() string {
+	return MiddlewareID
+}
+
+func (l *callLogger) HandleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	start := time.Now()
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	duration := time.Since(start)
+
+	logger := l.logger
+	if logger == nil {
+		logger = sloglambda.LoggerFromContext(ctx)
+	}
+
+	level := l.level
+	if err != nil {
+		level = l.errorLevel
+	}
+	if !logger.Enabled(ctx, level) {
+		return out, metadata, err
+	}
+
+	attrs := []any{
+		slog.String(kAWSService, awsmiddleware.GetServiceID(ctx)),
+		slog.String(kAWSOperation, awsmiddleware.GetOperationName(ctx)),
+		slog.Int64(kAWSDuration, duration.Milliseconds()),
+	}
+	if status := responseStatus(metadata, err); status != 0 {
+		attrs = append(attrs, slog.Int(kAWSStatus, status))
+	}
+	if requestID := responseRequestID(metadata, err); requestID != "" {
+		attrs = append(attrs, slog.String(kAWSRequestID, requestID))
+	}
+	if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 0 {
+		attrs = append(attrs, slog.Int(kAWSRetries, len(results.Results)-1))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String(kAWSError, err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, "AWS call completed", sloglambda.Type(CallLogType), slog.Group(kAWSGroup, attrs...))
+
+	return out, metadata, err
+}
+
+// responseStatus returns the HTTP status code of the call's last response, or 0 if there was none.
+func responseStatus(metadata middleware.Metadata, err error) int {
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		return responseErr.HTTPStatusCode()
+	}
+	if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok && resp != nil {
+		return resp.StatusCode
+	}
+	return 0
+}
+
+// responseRequestID returns the AWS request ID of the call, or "" if the service did not return one.
+func responseRequestID(metadata middleware.Metadata, err error) string {
+	if requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		return requestID
+	}
+	var responseErr interface{ ServiceRequestID() string }
+	if errors.As(err, &responseErr) {
+		return responseErr.ServiceRequestID()
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package sloglambdasdk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdasdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// call runs a GetObject call through a middleware stack with the call logging middleware, with a
+// final handler returning the metadata and error set by result.
+func call(t *testing.T, logger *slog.Logger, result func(metadata *middleware.Metadata) error, options ...sloglambdasdk.Option) error {
+	t.Helper()
+
+	stack := middleware.NewStack("GetObject", smithyhttp.NewStackRequest)
+	require.NoError(t, stack.Initialize.Add(&awsmiddleware.RegisterServiceMetadata{ServiceID: "S3", OperationName: "GetObject"}, middleware.Before))
+	require.NoError(t, sloglambdasdk.CallLogging(logger, options...)(stack))
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(func(context.Context, interface{}) (interface{}, middleware.Metadata, error) {
+		var metadata middleware.Metadata
+		err := result(&metadata)
+		return nil, metadata, err
+	}), stack)
+
+	_, _, err := handler.Handle(context.Background(), struct{}{})
+	return err
+}
+
+func TestCallLogging(t *testing.T) {
+	t.Run("logs successful calls", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug)))
+
+		err := call(t, logger, func(metadata *middleware.Metadata) error {
+			awsmiddleware.SetRequestIDMetadata(metadata, "req-1")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"level":"DEBUG"`)
+		assert.Contains(t, buffer.String(), `"type":"aws.call"`)
+		assert.Contains(t, buffer.String(), `"service":"S3"`)
+		assert.Contains(t, buffer.String(), `"operation":"GetObject"`)
+		assert.Contains(t, buffer.String(), `"requestId":"req-1"`)
+		assert.Contains(t, buffer.String(), `"durationMs":`)
+		assert.NotContains(t, buffer.String(), `"error"`)
+	})
+
+	t.Run("logs failed calls", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		err := call(t, logger, func(*middleware.Metadata) error {
+			return &awshttp.ResponseError{
+				ResponseError: &smithyhttp.ResponseError{
+					Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+					Err:      errors.New("slow down"),
+				},
+				RequestID: "req-2",
+			}
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, buffer.String(), `"level":"ERROR"`)
+		assert.Contains(t, buffer.String(), `"status":503`)
+		assert.Contains(t, buffer.String(), `"requestId":"req-2"`)
+		assert.Contains(t, buffer.String(), "slow down")
+	})
+
+	t.Run("uses the configured levels", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		err := call(t, logger, func(*middleware.Metadata) error { return nil }, sloglambdasdk.WithLevels(slog.LevelInfo, slog.LevelWarn))
+
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"level":"INFO"`)
+	})
+
+	t.Run("uses the logger from the context", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		stack := middleware.NewStack("GetObject", smithyhttp.NewStackRequest)
+		require.NoError(t, sloglambdasdk.CallLogging(nil, sloglambdasdk.WithLevels(slog.LevelInfo, slog.LevelError))(stack))
+
+		handler := middleware.DecorateHandler(middleware.HandlerFunc(func(context.Context, interface{}) (interface{}, middleware.Metadata, error) {
+			return nil, middleware.Metadata{}, nil
+		}), stack)
+
+		_, _, err := handler.Handle(sloglambda.ContextWithLogger(context.Background(), logger), struct{}{})
+
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"type":"aws.call"`)
+	})
+}
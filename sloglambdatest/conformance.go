@@ -0,0 +1,57 @@
+package sloglambdatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+)
+
+// RunConformance runs testing/slogtest against the slog.Handler returned by newHandler, so a
+// Handler wrapped with decorators (see sloglambda.Chain) or configured with options such as
+// redaction, sampling, or additional outputs can be verified to still satisfy the semantics of
+// slog.Handler:
+//
+//	func TestLoggerConformance(t *testing.T) {
+//		sloglambdatest.RunConformance(t, func(w io.Writer) slog.Handler {
+//			return sloglambda.Chain(app.NewHandler(w), app.Decorators()...)
+//		})
+//	}
+//
+// newHandler is called for every case and must return a handler writing JSON or text records to w,
+// at DEBUG or lower, that does not drop the records of the cases. Text records are parsed with
+// ParseText.
+func RunConformance(t *testing.T, newHandler func(w io.Writer) slog.Handler) {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		buffer.Reset()
+		return newHandler(buffer)
+	}, func(t *testing.T) map[string]any {
+		return parseRecord(t, buffer.Bytes())
+	})
+}
+
+// parseRecord parses a JSON or text record, failing the test if it is neither.
+func parseRecord(t *testing.T, p []byte) map[string]any {
+	t.Helper()
+
+	p = bytes.TrimSpace(p)
+	if len(p) > 0 && p[0] == '{' {
+		var record map[string]any
+		if err := json.Unmarshal(p, &record); err != nil {
+			t.Fatalf("invalid JSON record %q: %v", p, err)
+		}
+		return record
+	}
+
+	record, err := ParseText(string(p))
+	if err != nil {
+		t.Fatalf("invalid text record %q: %v", p, err)
+	}
+	return record
+}
@@ -0,0 +1,45 @@
+package sloglambdatest_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdatest"
+)
+
+func TestRunConformance(t *testing.T) {
+	keepAll := sloglambda.FilterDecorator(func(context.Context, slog.Record) bool { return true })
+
+	t.Run("JSON", func(t *testing.T) {
+		sloglambdatest.RunConformance(t, func(w io.Writer) slog.Handler {
+			return sloglambda.NewHandler(w, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug))
+		})
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		sloglambdatest.RunConformance(t, func(w io.Writer) slog.Handler {
+			return sloglambda.NewHandler(w, sloglambda.WithText(), sloglambda.WithLevel(slog.LevelDebug))
+		})
+	})
+
+	t.Run("decorated", func(t *testing.T) {
+		sloglambdatest.RunConformance(t, func(w io.Writer) slog.Handler {
+			h := sloglambda.NewHandler(w,
+				sloglambda.WithJSON(),
+				sloglambda.WithLevel(slog.LevelDebug),
+				sloglambda.WithMessageSampling(100, 1),
+				sloglambda.WithOutput(sloglambda.FormatText, io.Discard, sloglambda.OutputRedact("secret")),
+			)
+			return sloglambda.Chain(h, keepAll)
+		})
+	})
+
+	t.Run("Enrich", func(t *testing.T) {
+		sloglambdatest.RunConformance(t, func(w io.Writer) slog.Handler {
+			return sloglambda.Chain(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}), sloglambda.Enrich, keepAll)
+		})
+	})
+}
@@ -0,0 +1,170 @@
+// Package sloglambdatest provides helpers for testing loggers configured with sloglambda.
+package sloglambdatest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+)
+
+const (
+	envLogLevel  = "AWS_LAMBDA_LOG_LEVEL"
+	envLogFormat = "AWS_LAMBDA_LOG_FORMAT"
+)
+
+// LogLevels are the values of AWS_LAMBDA_LOG_LEVEL RunMatrix runs with. The empty string leaves the
+// variable unset.
+var LogLevels = []string{"", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// LogFormats are the values of AWS_LAMBDA_LOG_FORMAT RunMatrix runs with. The empty string leaves
+// the variable unset.
+var LogFormats = []string{"", "JSON", "Text"}
+
+// recordLevels are the levels RunMatrix logs a record at, by their Lambda names.
+var recordLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// Environment is a combination of the Lambda advanced logging controls.
+type Environment struct {
+	LogLevel  string
+	LogFormat string
+}
+
+// Name returns a name for the environment, for use as a subtest name.
+func (e Environment) Name() string {
+	return "level=" + orUnset(e.LogLevel) + "/format=" + orUnset(e.LogFormat)
+}
+
+// Level returns the minimum level of the records a Handler writes in the environment: the log level,
+// or INFO when it is unset.
+func (e Environment) Level() slog.Level {
+	if e.LogLevel == "" {
+		return slog.LevelInfo
+	}
+	level, _ := sloglambda.ParseLevel(e.LogLevel)
+	return level
+}
+
+// JSON reports whether a Handler writes JSON records in the environment.
+func (e Environment) JSON() bool {
+	format, err := sloglambda.ParseFormat(e.LogFormat)
+	return err == nil && format == sloglambda.FormatJSON
+}
+
+// Environments returns every combination of LogLevels and LogFormats.
+func Environments() []Environment {
+	environments := make([]Environment, 0, len(LogLevels)*len(LogFormats))
+	for _, level := range LogLevels {
+		for _, format := range LogFormats {
+			environments = append(environments, Environment{LogLevel: level, LogFormat: format})
+		}
+	}
+	return environments
+}
+
+// RunMatrix runs a subtest for every Environment, validating a logger setup against the full matrix
+// of the Lambda advanced logging controls:
+//
+//	func TestLogger(t *testing.T) {
+//		sloglambdatest.RunMatrix(t, func(w io.Writer) *slog.Logger {
+//			return app.NewLogger(w)
+//		})
+//	}
+//
+// Each subtest sets AWS_LAMBDA_LOG_LEVEL and AWS_LAMBDA_LOG_FORMAT, creates a logger writing to a
+// buffer with newLogger, and logs a record at every level from TRACE to FATAL. It fails unless the
+// records at or above the environment's level, and only those, are written, one per line, as JSON
+// objects with the expected "level" and "msg" fields when the format is JSON, and as text lines
+// holding the level and message otherwise.
+//
+// RunMatrix sets environment variables, so it cannot be used in parallel tests.
+func RunMatrix(t *testing.T, newLogger func(w io.Writer) *slog.Logger) {
+	t.Helper()
+
+	for _, env := range Environments() {
+		t.Run(env.Name(), func(t *testing.T) {
+			setenv(t, envLogLevel, env.LogLevel)
+			setenv(t, envLogFormat, env.LogFormat)
+
+			buffer := new(bytes.Buffer)
+			logger := newLogger(buffer)
+
+			var want []string
+			for _, name := range recordLevels {
+				level, _ := sloglambda.ParseLevel(name)
+				logger.Log(context.Background(), level, message(name))
+				if level >= env.Level() {
+					want = append(want, name)
+				}
+			}
+
+			lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+			if buffer.Len() == 0 {
+				lines = nil
+			}
+			if len(lines) != len(want) {
+				t.Fatalf("wrote %d records, want %d (%s):\n%s", len(lines), len(want), strings.Join(want, ", "), buffer.String())
+			}
+
+			for i, line := range lines {
+				checkRecord(t, env, line, want[i])
+			}
+		})
+	}
+}
+
+func checkRecord(t *testing.T, env Environment, line, level string) {
+	t.Helper()
+
+	var record map[string]any
+	isJSON := json.Unmarshal([]byte(line), &record) == nil
+
+	if !env.JSON() {
+		if isJSON {
+			t.Errorf("wrote a JSON record, want text: %s", line)
+		}
+		if !strings.Contains(line, level) || !strings.Contains(line, message(level)) {
+			t.Errorf("text record does not hold level %s and message %q: %s", level, message(level), line)
+		}
+		return
+	}
+
+	if !isJSON {
+		t.Errorf("wrote a text record, want a JSON object: %s", line)
+		return
+	}
+	if record[slog.LevelKey] != level {
+		t.Errorf("JSON record %q = %v, want %s: %s", slog.LevelKey, record[slog.LevelKey], level, line)
+	}
+	if record[slog.MessageKey] != message(level) {
+		t.Errorf("JSON record %q = %v, want %q: %s", slog.MessageKey, record[slog.MessageKey], message(level), line)
+	}
+}
+
+// setenv sets the environment variable key for the duration of the test, or unsets it when value is
+// empty.
+func setenv(t *testing.T, key, value string) {
+	t.Setenv(key, value)
+	if value == "" {
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func message(level string) string {
+	return "sloglambdatest " + level + " record"
+}
+
+func orUnset(value string) string {
+	if value == "" {
+		return "unset"
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package sloglambdatest_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdatest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMatrix(t *testing.T) {
+	sloglambdatest.RunMatrix(t, func(w io.Writer) *slog.Logger {
+		return slog.New(sloglambda.NewHandler(w))
+	})
+}
+
+func TestEnvironment(t *testing.T) {
+	assert.Len(t, sloglambdatest.Environments(), len(sloglambdatest.LogLevels)*len(sloglambdatest.LogFormats))
+
+	env := sloglambdatest.Environment{LogLevel: "warn", LogFormat: "JSON"}
+	assert.Equal(t, slog.LevelWarn, env.Level())
+	assert.True(t, env.JSON())
+	assert.Equal(t, "level=warn/format=JSON", env.Name())
+
+	env = sloglambdatest.Environment{}
+	assert.Equal(t, slog.LevelInfo, env.Level())
+	assert.False(t, env.JSON())
+	assert.Equal(t, "level=unset/format=unset", env.Name())
+}
@@ -0,0 +1,131 @@
+package sloglambdatest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseText parses a record written in the text format of a sloglambda.Handler, the inverse of
+// sloglambda.TextEncoder, so tests can make assertions about text records the way they would about
+// decoded JSON records.
+//
+// The fields of groups are returned as nested maps, keyed by the segments of their dot separated
+// keys. Every other value is returned as a string: quoted values are unquoted, and unquoted values,
+// such as numbers, booleans, and "null", are returned as written. A trailing newline is ignored.
+func ParseText(line string) (map[string]any, error) {
+	p := &textParser{s: strings.TrimSuffix(line, "\n")}
+	record := make(map[string]any)
+
+	for !p.done() {
+		if p.i > 0 {
+			if err := p.expect(' '); err != nil {
+				return nil, err
+			}
+		}
+
+		path, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := p.token(false)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := set(record, path, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+type textParser struct {
+	s string
+	i int
+}
+
+func (p *textParser) done() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *textParser) expect(c byte) error {
+	if p.done() || p.s[p.i] != c {
+		return fmt.Errorf("offset %d: expected %q", p.i, c)
+	}
+	p.i++
+	return nil
+}
+
+// path parses the dot separated segments of a key.
+func (p *textParser) path() ([]string, error) {
+	var path []string
+	for {
+		segment, err := p.token(true)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, segment)
+
+		if p.done() || p.s[p.i] != '.' {
+			return path, nil
+		}
+		p.i++
+	}
+}
+
+// token parses a quoted string, or an unquoted token ending at a space, or for keys at an equals
+// sign or dot.
+func (p *textParser) token(key bool) (string, error) {
+	if !p.done() && p.s[p.i] == '"' {
+		quoted, err := strconv.QuotedPrefix(p.s[p.i:])
+		if err != nil {
+			return "", fmt.Errorf("offset %d: %w", p.i, err)
+		}
+		p.i += len(quoted)
+		return strconv.Unquote(quoted)
+	}
+
+	start := p.i
+	for !p.done() {
+		c := p.s[p.i]
+		if c == ' ' || (key && (c == '=' || c == '.')) {
+			break
+		}
+		if c == '"' || c == '=' {
+			return "", fmt.Errorf("offset %d: unexpected %q in unquoted token", p.i, c)
+		}
+		p.i++
+	}
+	if p.i == start {
+		return "", fmt.Errorf("offset %d: empty token", start)
+	}
+	return p.s[start:p.i], nil
+}
+
+// set stores value in record at path, creating the groups along it.
+func set(record map[string]any, path []string, value string) error {
+	for _, key := range path[:len(path)-1] {
+		switch group := record[key].(type) {
+		case nil:
+			next := make(map[string]any)
+			record[key] = next
+			record = next
+		case map[string]any:
+			record = group
+		default:
+			return fmt.Errorf("field %q is both a value and a group", strings.Join(path, "."))
+		}
+	}
+
+	key := path[len(path)-1]
+	if _, ok := record[key]; ok {
+		return fmt.Errorf("duplicate field %q", strings.Join(path, "."))
+	}
+	record[key] = value
+	return nil
+}
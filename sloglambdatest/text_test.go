@@ -0,0 +1,98 @@
+package sloglambdatest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseText(t *testing.T) {
+	t.Run("parses fields and groups", func(t *testing.T) {
+		record, err := sloglambdatest.ParseText(`count=3 level="INFO" msg="hello world" "odd key"="a=b" record.functionName="orders" record."dotted.key"=null` + "\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"count":   "3",
+			"level":   "INFO",
+			"msg":     "hello world",
+			"odd key": "a=b",
+			"record":  map[string]any{"functionName": "orders", "dotted.key": "null"},
+		}, record)
+	})
+
+	t.Run("empty record", func(t *testing.T) {
+		record, err := sloglambdatest.ParseText("\n")
+		require.NoError(t, err)
+		assert.Empty(t, record)
+	})
+
+	t.Run("invalid records", func(t *testing.T) {
+		for _, line := range []string{
+			`msg`,
+			`msg=`,
+			`msg="unterminated`,
+			`a=1  b=2`,
+			`a=x"y`,
+			`a=1 a=2`,
+			`a=1 a.b=2`,
+		} {
+			_, err := sloglambdatest.ParseText(line)
+			assert.Error(t, err, line)
+		}
+	})
+}
+
+type fuzzStringer string
+
+func (s fuzzStringer) String() string {
+	return string(s)
+}
+
+// toMap converts encoder fields to the structure returned by ParseText.
+func toMap(fields sloglambda.Fields) map[string]any {
+	record := make(map[string]any, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case sloglambda.Fields:
+			record[k] = toMap(v)
+		case fuzzStringer:
+			record[k] = string(v)
+		default:
+			record[k] = v
+		}
+	}
+	return record
+}
+
+func FuzzTextRoundTrip(f *testing.F) {
+	f.Add("msg", "hello world")
+	f.Add("key with spaces", `value with "quotes"`)
+	f.Add("a=b", "c=d")
+	f.Add("dotted.key", "line\nbreak\r\n")
+	f.Add("", "")
+	f.Add("ünïcödé", "日本語 🚀")
+	f.Add("tab\tkey", "\x00\xff")
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		fields := sloglambda.Fields{
+			"group": sloglambda.Fields{key: value, "stringer": fuzzStringer(value)},
+		}
+		fields[key] = value
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, sloglambda.TextEncoder{}.Encode(buf, fields))
+
+		line := buf.String()
+		require.Equal(t, 1, strings.Count(line, "\n"), "record spans several lines: %q", line)
+		require.True(t, strings.HasSuffix(line, "\n"))
+
+		record, err := sloglambdatest.ParseText(line)
+		require.NoError(t, err, "line: %q", line)
+		assert.Equal(t, toMap(fields), record, "line: %q", line)
+	})
+}
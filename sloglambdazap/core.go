@@ -0,0 +1,143 @@
+// Package sloglambdazap provides a zapcore.Core backed by a sloglambda Handler, so services that
+// log with zap get the same Lambda aware output as code that logs with slog.
+//
+// It is a separate module so that sloglambda itself does not depend on zap.
+package sloglambdazap
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	kZapLogger = "logger"
+	kZapStack  = "stack"
+)
+
+var levelFatal, _ = sloglambda.ParseLevel("FATAL")
+
+// Core is a zapcore.Core that writes entries to a slog.Handler:
+//
+//	logger := zap.New(sloglambdazap.NewCore(handler).Context(ctx))
+//
+// Zap loggers do not carry a context, so the records are written with the context given to Context,
+// which should be the context of the invocation so the records include its Lambda record and request
+// ID.
+type Core struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+// NewCore creates a Core writing to the given handler.
+func NewCore(h slog.Handler) *Core {
+	return &Core{
+		handler: h,
+		ctx:     context.Background(),
+	}
+}
+
+// Context returns a copy of the core that writes records with the given context.
+func (c *Core) Context(ctx context.Context) *Core {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
+
+// Enabled implements zapcore.LevelEnabler, deferring to the level of the handler.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(c.ctx, Level(level))
+}
+
+// With implements zapcore.Core, adding the fields to the handler. Fields following a zap.Namespace
+// are added in a group.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	cc := *c
+	start := 0
+	for i, field := range fields {
+		if field.Type == zapcore.NamespaceType {
+			cc.handler = withAttrs(cc.handler, fieldAttrs(fields[start:i])).WithGroup(field.Key)
+			start = i + 1
+		}
+	}
+	cc.handler = withAttrs(cc.handler, fieldAttrs(fields[start:]))
+	return &cc
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core, writing the entry as a record at the level mapped by Level. The
+// name of a named logger is written as "logger", and the stack trace captured by zap as "stack".
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := slog.NewRecord(entry.Time, Level(entry.Level), entry.Message, entry.Caller.PC)
+	if entry.LoggerName != "" {
+		record.AddAttrs(slog.String(kZapLogger, entry.LoggerName))
+	}
+	if entry.Stack != "" {
+		record.AddAttrs(slog.String(kZapStack, entry.Stack))
+	}
+	record.AddAttrs(fieldAttrs(fields)...)
+
+	return c.handler.Handle(c.ctx, record)
+}
+
+// Sync implements zapcore.Core. It does nothing: the records of an invocation are flushed when it
+// ends (see sloglambda.Handler.EndInvocation).
+func (c *Core) Sync() error {
+	return nil
+}
+
+// Level returns the slog level a zap level is written at. The development panic level is written as
+// ERROR, and the panic and fatal levels as FATAL.
+func Level(level zapcore.Level) slog.Level {
+	switch level {
+	case zapcore.DebugLevel:
+		return slog.LevelDebug
+	case zapcore.InfoLevel:
+		return slog.LevelInfo
+	case zapcore.WarnLevel:
+		return slog.LevelWarn
+	case zapcore.ErrorLevel, zapcore.DPanicLevel:
+		return slog.LevelError
+	default:
+		if level < zapcore.DebugLevel {
+			return slog.LevelDebug
+		}
+		return levelFatal
+	}
+}
+
+func withAttrs(h slog.Handler, attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.WithAttrs(attrs)
+}
+
+// fieldAttrs converts zap fields to attributes, keeping their order. Fields following a
+// zap.Namespace are nested in a group.
+func fieldAttrs(fields []zapcore.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i, field := range fields {
+		if field.Type == zapcore.NamespaceType {
+			return append(attrs, slog.Attr{Key: field.Key, Value: slog.GroupValue(fieldAttrs(fields[i+1:])...)})
+		}
+
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		for _, key := range slices.Sorted(maps.Keys(encoder.Fields)) {
+			attrs = append(attrs, slog.Any(key, encoder.Fields[key]))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,71 @@
+package sloglambdazap_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/maddiesch/slog-lambda/sloglambdazap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	t.Run("writes entries through the handler", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := zap.New(sloglambdazap.NewCore(sloglambda.NewHandler(buffer, sloglambda.WithJSON())))
+
+		logger.Named("orders").With(zap.String("user", "alice")).Warn("careful", zap.Int("attempt", 2), zap.Error(errors.New("boom")))
+
+		assert.Contains(t, buffer.String(), `"level":"WARN"`)
+		assert.Contains(t, buffer.String(), `"msg":"careful"`)
+		assert.Contains(t, buffer.String(), `"logger":"orders"`)
+		assert.Contains(t, buffer.String(), `"user":"alice"`)
+		assert.Contains(t, buffer.String(), `"attempt":2`)
+		assert.Contains(t, buffer.String(), `"error":"boom"`)
+	})
+
+	t.Run("nests namespaced fields", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := zap.New(sloglambdazap.NewCore(sloglambda.NewHandler(buffer, sloglambda.WithJSON())))
+
+		logger.Info("nested", zap.Namespace("order"), zap.String("id", "o-1"))
+
+		assert.Contains(t, buffer.String(), `"order":{"id":"o-1"}`)
+	})
+
+	t.Run("uses the level of the handler", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := zap.New(sloglambdazap.NewCore(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelInfo))))
+
+		logger.Debug("hidden")
+		assert.Empty(t, buffer.String())
+
+		logger.Info("shown")
+		assert.Contains(t, buffer.String(), `"msg":"shown"`)
+	})
+
+	t.Run("writes with the context of the invocation", func(t *testing.T) {
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+
+		buffer := new(bytes.Buffer)
+		logger := zap.New(sloglambdazap.NewCore(sloglambda.NewHandler(buffer, sloglambda.WithJSON())).Context(ctx))
+
+		logger.Info("invoked")
+
+		assert.Contains(t, buffer.String(), `"requestId":"req-1"`)
+	})
+}
+
+func TestLevel(t *testing.T) {
+	fatal, _ := sloglambda.ParseLevel("FATAL")
+
+	assert.Equal(t, slog.LevelDebug, sloglambdazap.Level(zapcore.DebugLevel))
+	assert.Equal(t, slog.LevelError, sloglambdazap.Level(zapcore.DPanicLevel))
+	assert.Equal(t, fatal, sloglambdazap.Level(zapcore.FatalLevel))
+}
@@ -0,0 +1,12 @@
+module github.com/maddiesch/slog-lambda/sloglambdazap
+
+go 1.23.0
+
+require (
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/maddiesch/slog-lambda v0.0.0
+	github.com/stretchr/testify v1.8.0
+	go.uber.org/zap v1.27.0
+)
+
+replace github.com/maddiesch/slog-lambda => ../
@@ -0,0 +1,99 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	kSnapStartRestore = "snapstartRestore"
+	kRestoreLatency   = "restoreLatencyMs"
+)
+
+// WithSnapStart configures the Handler to tag the records written after the execution environment
+// is restored from a SnapStart snapshot with "snapstartRestore": true, so records affected by the
+// different uniqueness and clock assumptions after a restore can be told apart.
+//
+// The records tagged are those written between the restore and the first invocation, and those of
+// the first invocation. When the restore time is known (see MarkSnapStartRestore) they also carry a
+// "restoreLatencyMs" field: the time between the restore and the start of the first invocation, or
+// the time since the restore for records written before it.
+//
+// A restore is detected when MarkSnapStartRestore is called, or, when the initialization type is
+// "snap-start" (see InitializationType), at the first invocation, as environments initialized for
+// SnapStart serve no invocations before the snapshot is taken.
+func WithSnapStart() Option {
+	return func(h *Handler) {
+		h.snapStart = true
+	}
+}
+
+// MarkSnapStartRestore records that the execution environment was restored from a SnapStart
+// snapshot. Call it first thing in the runtime's after restore hook so records can report the
+// restore latency.
+func MarkSnapStartRestore() {
+	snapStartState.restore(time.Now())
+}
+
+// snapStart tracks the SnapStart restore of the execution environment shared by all Handlers in the
+// process.
+type snapStart struct {
+	mu         sync.Mutex
+	restoredAt time.Time
+	requestID  string
+	latency    time.Duration
+	done       bool
+}
+
+var snapStartState snapStart
+
+func (s *snapStart) restore(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.restoredAt = now
+	s.requestID = ""
+	s.latency = 0
+	s.done = false
+}
+
+// attrs returns the attributes of a record written at now for the given request ID, or nil when
+// the record does not follow a restore.
+func (s *snapStart) attrs(requestID string, now time.Time) []slog.Attr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return nil
+	}
+	if s.restoredAt.IsZero() && InitializationType() != InitializationSnapStart {
+		return nil
+	}
+
+	latency := now.Sub(s.restoredAt)
+	switch {
+	case requestID == "":
+		if s.restoredAt.IsZero() {
+			// Initialization before the snapshot was taken.
+			return nil
+		}
+		if s.requestID != "" {
+			latency = s.latency
+		}
+	case s.requestID == "":
+		s.requestID = requestID
+		s.latency = latency
+	case s.requestID == requestID:
+		latency = s.latency
+	default:
+		s.done = true
+		return nil
+	}
+
+	attrs := []slog.Attr{slog.Bool(kSnapStartRestore, true)}
+	if !s.restoredAt.IsZero() {
+		attrs = append(attrs, slog.Int64(kRestoreLatency, latency.Milliseconds()))
+	}
+	return attrs
+}
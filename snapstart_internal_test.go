@@ -0,0 +1,91 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetSnapStart(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		snapStartState.mu.Lock()
+		defer snapStartState.mu.Unlock()
+		snapStartState.restoredAt = time.Time{}
+		snapStartState.requestID = ""
+		snapStartState.latency = 0
+		snapStartState.done = false
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestWithSnapStart(t *testing.T) {
+	invocation := func(requestID string) context.Context {
+		return lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: requestID})
+	}
+
+	t.Run("tags the first invocation after a restore", func(t *testing.T) {
+		resetSnapStart(t)
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(NewHandler(buffer, WithJSON(), WithSnapStart()))
+
+		logger.Info("before restore")
+		assert.NotContains(t, buffer.String(), kSnapStartRestore)
+		buffer.Reset()
+
+		MarkSnapStartRestore()
+		logger.Info("restored")
+		assert.Contains(t, buffer.String(), `"snapstartRestore":true`)
+		assert.Contains(t, buffer.String(), `"restoreLatencyMs":`)
+		buffer.Reset()
+
+		logger.InfoContext(invocation("snap-1"), "first")
+		logger.InfoContext(invocation("snap-1"), "first again")
+
+		var first, again map[string]any
+		lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		require.NoError(t, json.Unmarshal(lines[0], &first))
+		require.NoError(t, json.Unmarshal(lines[1], &again))
+		assert.Equal(t, true, first[kSnapStartRestore])
+		assert.Equal(t, first[kRestoreLatency], again[kRestoreLatency])
+		buffer.Reset()
+
+		logger.InfoContext(invocation("snap-2"), "second")
+		assert.NotContains(t, buffer.String(), kSnapStartRestore)
+	})
+
+	t.Run("detects the initialization type", func(t *testing.T) {
+		resetSnapStart(t)
+		t.Setenv(lambdaEnvInitializationType, InitializationSnapStart)
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(NewHandler(buffer, WithJSON(), WithSnapStart()))
+
+		logger.Info("init")
+		assert.NotContains(t, buffer.String(), kSnapStartRestore)
+		buffer.Reset()
+
+		logger.InfoContext(invocation("snap-3"), "first")
+		assert.Contains(t, buffer.String(), `"snapstartRestore":true`)
+		assert.NotContains(t, buffer.String(), kRestoreLatency)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resetSnapStart(t)
+		MarkSnapStartRestore()
+
+		buffer := new(bytes.Buffer)
+		slog.New(NewHandler(buffer, WithJSON())).Info("restored")
+		assert.NotContains(t, buffer.String(), kSnapStartRestore)
+	})
+}
@@ -0,0 +1,67 @@
+package sloglambda
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SourceFormat controls how source code information is represented in a record.
+type SourceFormat int
+
+const (
+	// SourceGroup writes the source as a group with "function", "file", and "line" fields.
+	SourceGroup SourceFormat = iota
+	// SourceShort writes the source as a single string of the form "pkg/file.go:123".
+	SourceShort
+	// SourceShortFunction writes the source as a single string of the form
+	// "pkg/file.go:123 (pkg.Function)".
+	SourceShortFunction
+)
+
+// WithSourceFormat configures the Handler to include source code information in log messages using
+// the given format. It implies WithSource.
+func WithSourceFormat(format SourceFormat) Option {
+	return func(h *Handler) {
+		h.source = true
+		h.sourceFormat = format
+	}
+}
+
+func (h *Handler) sourceAttr(pc uintptr) slog.Attr {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+
+	switch h.sourceFormat {
+	case SourceShort:
+		return slog.String(slog.SourceKey, shortSourceFile(frame))
+	case SourceShortFunction:
+		return slog.String(slog.SourceKey, fmt.Sprintf("%s (%s)", shortSourceFile(frame), shortFunctionName(frame.Function)))
+	default:
+		return slog.Group(slog.SourceKey,
+			slog.String("function", frame.Function),
+			slog.String("file", frame.File),
+			slog.Int("line", frame.Line),
+		)
+	}
+}
+
+// shortSourceFile returns the file's parent directory, base name, and line, e.g. "pkg/file.go:123".
+func shortSourceFile(frame runtime.Frame) string {
+	dir, file := filepath.Split(frame.File)
+	if dir = filepath.Base(dir); dir != "." && dir != string(filepath.Separator) {
+		file = dir + "/" + file
+	}
+	return fmt.Sprintf("%s:%d", file, frame.Line)
+}
+
+// shortFunctionName strips the import path from a fully qualified function name, leaving the
+// package name, e.g. "github.com/org/pkg.Function" becomes "pkg.Function".
+func shortFunctionName(function string) string {
+	if i := strings.LastIndexByte(function, '/'); i >= 0 {
+		return function[i+1:]
+	}
+	return function
+}
@@ -0,0 +1,39 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSourceFormat(t *testing.T) {
+	t.Run("SourceShort", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSourceFormat(sloglambda.SourceShort)))
+
+		logger.Info(t.Name())
+
+		assert.Regexp(t, `"source":"[^"/]+/source_test\.go:\d+"`, buffer.String())
+	})
+
+	t.Run("SourceShortFunction", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithSourceFormat(sloglambda.SourceShortFunction)))
+
+		logger.Info(t.Name())
+
+		assert.Regexp(t, `source="[^"/]+/source_test\.go:\d+ \(slog-lambda_test\.TestWithSourceFormat\.func2\)"`, buffer.String())
+	})
+
+	t.Run("SourceGroup", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithSourceFormat(sloglambda.SourceGroup)))
+
+		logger.Info(t.Name())
+
+		assert.Contains(t, buffer.String(), `"source":{`)
+	})
+}
@@ -0,0 +1,135 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SplunkWriter is an io.Writer that sends the records written by a Handler to a Splunk HTTP Event
+// Collector, avoiding the latency of forwarding CloudWatch Logs to Splunk.
+//
+// The Handler must write JSON (see WithJSON). Records are buffered and sent in batches, when the
+// batch is full, when the Handler ends an invocation (see Handler.EndInvocation), and on Flush or
+// Close. Each record is sent as an event whose sourcetype is the record's type and whose source is
+// the function name.
+type SplunkWriter struct {
+	endpoint string
+	token    string
+	channel  string
+	index    string
+	batch    *httpBatch
+}
+
+// NewSplunkWriter creates a SplunkWriter that sends events to the HTTP Event Collector event
+// endpoint, for example "https://splunk.example.com:8088/services/collector/event", authenticating
+// with token.
+func NewSplunkWriter(endpoint, token string) *SplunkWriter {
+	w := &SplunkWriter{
+		endpoint: endpoint,
+		token:    token,
+	}
+	w.batch = newHTTPBatch("splunk", w.send)
+	return w
+}
+
+// WithChannel configures the channel identifier sent with every request, required when the HTTP
+// Event Collector has indexer acknowledgement enabled.
+func (w *SplunkWriter) WithChannel(channel string) *SplunkWriter {
+	w.channel = channel
+	return w
+}
+
+// WithIndex configures the index events are written to, instead of the token's default index.
+func (w *SplunkWriter) WithIndex(index string) *SplunkWriter {
+	w.index = index
+	return w
+}
+
+// WithClient configures the http.Client used to send events.
+func (w *SplunkWriter) WithClient(client *http.Client) *SplunkWriter {
+	w.batch.client = client
+	return w
+}
+
+// WithBatchSize configures the number of records buffered before they are sent.
+func (w *SplunkWriter) WithBatchSize(size int) *SplunkWriter {
+	w.batch.batchSize = size
+	return w
+}
+
+// WithTimeout configures how long the SplunkWriter waits for a batch to be sent.
+func (w *SplunkWriter) WithTimeout(timeout time.Duration) *SplunkWriter {
+	w.batch.timeout = timeout
+	return w
+}
+
+// WithGzip configures the SplunkWriter to gzip compress batches whose payload is at least minSize
+// bytes, trading CPU time for fewer bytes sent. A minSize of zero compresses every batch.
+func (w *SplunkWriter) WithGzip(minSize int) *SplunkWriter {
+	w.batch.withGzip(minSize)
+	return w
+}
+
+// Write implements io.Writer.
+func (w *SplunkWriter) Write(p []byte) (int, error) {
+	return w.batch.write(p)
+}
+
+// Flush sends the buffered records.
+func (w *SplunkWriter) Flush() error {
+	return w.batch.Flush()
+}
+
+// Close sends the buffered records.
+func (w *SplunkWriter) Close() error {
+	return w.Flush()
+}
+
+var _ io.WriteCloser = (*SplunkWriter)(nil)
+
+type splunkEvent struct {
+	Time       json.Number     `json:"time"`
+	Source     string          `json:"source,omitempty"`
+	SourceType string          `json:"sourcetype,omitempty"`
+	Index      string          `json:"index,omitempty"`
+	Event      json.RawMessage `json:"event"`
+}
+
+func (w *SplunkWriter) send(ctx context.Context, records []httpRecord) error {
+	body := new(bytes.Buffer)
+	encoder := json.NewEncoder(body)
+
+	for _, record := range records {
+		fields := record.fields
+
+		event := splunkEvent{
+			Time:  json.Number(fmt.Sprintf("%.3f", float64(recordTime(fields).UnixMilli())/1000)),
+			Index: w.index,
+			Event: record.raw,
+		}
+		event.SourceType, _ = fields[kLambdaLogType].(string)
+		_, event.Source = recordInvocation(fields)
+
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	req, _, err := w.batch.newRequest(ctx, w.endpoint, body.Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+w.token)
+	if w.channel != "" {
+		req.Header.Set("X-Splunk-Request-Channel", w.channel)
+	}
+
+	_, err = w.batch.do("splunk", req)
+	return err
+}
@@ -0,0 +1,55 @@
+package sloglambda_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplunkWriter(t *testing.T) {
+	type event struct {
+		Time       float64        `json:"time"`
+		Source     string         `json:"source"`
+		SourceType string         `json:"sourcetype"`
+		Index      string         `json:"index"`
+		Event      map[string]any `json:"event"`
+	}
+
+	var events []event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Splunk token-1", r.Header.Get("Authorization"))
+		assert.Equal(t, "channel-1", r.Header.Get("X-Splunk-Request-Channel"))
+
+		decoder := json.NewDecoder(r.Body)
+		for decoder.More() {
+			var e event
+			require.NoError(t, decoder.Decode(&e))
+			events = append(events, e)
+		}
+
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer server.Close()
+
+	w := sloglambda.NewSplunkWriter(server.URL, "token-1").WithChannel("channel-1").WithIndex("lambda")
+	handler := sloglambda.NewHandler(w, sloglambda.WithJSON(), sloglambda.WithType("audit.log"))
+	logger := slog.New(handler)
+
+	logger.Info("one")
+	logger.Warn("two")
+	require.NoError(t, handler.EndInvocation(context.Background()))
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "audit.log", events[0].SourceType)
+	assert.Equal(t, "test-function", events[0].Source)
+	assert.Equal(t, "lambda", events[0].Index)
+	assert.Greater(t, events[0].Time, float64(1e9))
+	assert.Equal(t, "two", events[1].Event["msg"])
+}
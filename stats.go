@@ -0,0 +1,72 @@
+package sloglambda
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the counters a Handler keeps about the records it has processed since it
+// was created.
+//
+// Handlers derived with WithAttrs and WithGroup share their counters with the Handler they were
+// created from.
+type Stats struct {
+	Trace uint64 // number of TRACE records written
+	Debug uint64 // number of DEBUG records written
+	Info  uint64 // number of INFO records written
+	Warn  uint64 // number of WARN records written
+	Error uint64 // number of ERROR records written
+	Fatal uint64 // number of FATAL records written
+
+	BytesWritten uint64 // total number of bytes written to the output
+	EncodeErrors uint64 // number of records that failed to encode
+	Dropped      uint64 // number of records dropped by sampling, suppression, or the invocation budget
+	QueueDropped uint64 // number of records dropped because the asynchronous queue was full
+}
+
+type handlerStats struct {
+	levels       [6]atomic.Uint64
+	bytesWritten atomic.Uint64
+	encodeErrors atomic.Uint64
+	dropped      atomic.Uint64
+	queueDropped atomic.Uint64
+}
+
+func (s *handlerStats) written(level slog.Level, n int) {
+	s.levels[levelIndex(level)].Add(1)
+	s.bytesWritten.Add(uint64(n))
+}
+
+// levelIndex buckets a level the same way lambdaLoggerLevelString names it.
+func levelIndex(l slog.Level) int {
+	switch {
+	case l < slog.LevelDebug:
+		return 0
+	case l < slog.LevelInfo:
+		return 1
+	case l < slog.LevelWarn:
+		return 2
+	case l < slog.LevelError:
+		return 3
+	case l < slog.LevelError+fatalLevelErrorOffset:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// Stats returns a snapshot of the Handler's counters.
+func (h *Handler) Stats() Stats {
+	return Stats{
+		Trace:        h.stats.levels[0].Load(),
+		Debug:        h.stats.levels[1].Load(),
+		Info:         h.stats.levels[2].Load(),
+		Warn:         h.stats.levels[3].Load(),
+		Error:        h.stats.levels[4].Load(),
+		Fatal:        h.stats.levels[5].Load(),
+		BytesWritten: h.stats.bytesWritten.Load(),
+		EncodeErrors: h.stats.encodeErrors.Load(),
+		Dropped:      h.stats.dropped.Load(),
+		QueueDropped: h.stats.queueDropped.Load(),
+	}
+}
@@ -0,0 +1,34 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Stats(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug), sloglambda.WithMessageSampling(1, 0))
+	logger := slog.New(handler).With("component", "test")
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.Error("encode", "value", func() {})
+
+	stats := handler.Stats()
+
+	assert.Equal(t, uint64(1), stats.Debug)
+	assert.Equal(t, uint64(1), stats.Info)
+	assert.Equal(t, uint64(1), stats.Warn)
+	assert.Equal(t, uint64(1), stats.Error)
+	assert.Equal(t, uint64(0), stats.Fatal)
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, uint64(1), stats.EncodeErrors)
+	assert.Less(t, uint64(0), stats.BytesWritten)
+}
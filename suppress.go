@@ -0,0 +1,80 @@
+package sloglambda
+
+import (
+	"regexp"
+	"strings"
+)
+
+type suppressRule struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// WithSuppress configures the Handler to drop records, at any level, whose message matches one of
+// the patterns. It is intended for noisy messages that cannot be fixed at the source, such as
+// warnings logged by third party libraries.
+//
+// A pattern wrapped in slashes, such as "/^retrying after [0-9]+ms$/", is a regular expression.
+// Any other pattern is a glob matched against the whole message, where "*" matches any sequence of
+// characters and "?" matches a single character.
+//
+// When the invocation ends (see Handler.EndInvocation) a summary record reporting the number of
+// records dropped by each pattern is written. The option can be given more than once.
+func WithSuppress(patterns ...string) Option {
+	return func(h *Handler) {
+		rules := h.suppress[:len(h.suppress):len(h.suppress)]
+		for _, pattern := range patterns {
+			re, err := compileSuppressPattern(pattern)
+			if err != nil {
+				h.invalidOption("WithSuppress: %v", err)
+				continue
+			}
+			rules = append(rules, suppressRule{pattern: pattern, re: re})
+		}
+		h.suppress = rules
+	}
+}
+
+func compileSuppressPattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, "/"); ok && len(expr) > 0 {
+		if expr, ok := strings.CutSuffix(expr, "/"); ok {
+			return regexp.Compile(expr)
+		}
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// suppressed returns the pattern matching the message, if any.
+func (h *Handler) suppressed(message string) (string, bool) {
+	for _, rule := range h.suppress {
+		if rule.re.MatchString(message) {
+			return rule.pattern, true
+		}
+	}
+	return "", false
+}
+
+func (i *invocation) silence(pattern string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.silenced == nil {
+		i.silenced = make(map[string]int)
+	}
+	i.silenced[pattern]++
+}
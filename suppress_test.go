@@ -0,0 +1,44 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSuppress(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+
+	buffer := new(bytes.Buffer)
+	handler := sloglambda.NewHandler(buffer, sloglambda.WithText(), sloglambda.WithoutTime(), sloglambda.WithSuppress("deprecated: *", `/^retrying after \d+ms$/`))
+	logger := slog.New(handler)
+
+	logger.WarnContext(ctx, "deprecated: use v2")
+	logger.WarnContext(ctx, "deprecated: region")
+	logger.InfoContext(ctx, "retrying after 100ms")
+	logger.InfoContext(ctx, "retrying after many ms")
+	logger.InfoContext(ctx, "not deprecated: anything")
+
+	require.NoError(t, handler.EndInvocation(ctx))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], `msg="retrying after many ms"`)
+	assert.Contains(t, lines[1], `msg="not deprecated: anything"`)
+	assert.Contains(t, lines[2], `msg="messages suppressed" pattern="/^retrying after \\d+ms$/"`)
+	assert.Contains(t, lines[2], "suppressed=1")
+	assert.Contains(t, lines[3], `msg="messages suppressed" pattern="deprecated: *"`)
+	assert.Contains(t, lines[3], "suppressed=2")
+
+	t.Run("with an invalid regular expression", func(t *testing.T) {
+		_, err := sloglambda.NewHandlerE(new(bytes.Buffer), sloglambda.WithSuppress("/(/"))
+		assert.ErrorIs(t, err, sloglambda.ErrInvalidOption)
+	})
+}
@@ -0,0 +1,146 @@
+package sloglambda
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var kTemplate = "template"
+
+// ExpandTemplate renders a message with "{name}" placeholders, returning the message and an
+// attribute for each placeholder.
+//
+// Placeholders are replaced, in order, by the leading args, and each one is also returned as an
+// attribute with the placeholder's name. The remaining args are key-value pairs or attributes, as
+// for Logger.Log. The template itself is returned as a "template" attribute so the records of a
+// message can be grouped regardless of their values:
+//
+//	msg, attrs := sloglambda.ExpandTemplate("order {orderId} shipped to {country}", id, "NZ")
+//
+// A placeholder name may contain letters, digits, "_", and "."; any other use of braces is kept
+// verbatim. Placeholders without a value are rendered as "{name}" and omitted from the attributes.
+func ExpandTemplate(template string, args ...any) (string, []slog.Attr) {
+	var (
+		msg   strings.Builder
+		attrs []slog.Attr
+	)
+
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := rest[start+1 : end]
+		if !isPlaceholderName(name) {
+			msg.WriteString(rest[:start+1])
+			rest = rest[start+1:]
+			continue
+		}
+
+		msg.WriteString(rest[:start])
+		if len(args) > 0 {
+			msg.WriteString(formatTemplateArg(args[0]))
+			attrs = append(attrs, slog.Any(name, args[0]))
+			args = args[1:]
+		} else {
+			msg.WriteString(rest[start : end+1])
+		}
+		rest = rest[end+1:]
+	}
+	msg.WriteString(rest)
+
+	attrs = append(attrs, slog.String(kTemplate, template))
+	return msg.String(), append(attrs, argsToAttrs(args)...)
+}
+
+// ExpandPrintf renders a message with fmt.Sprintf, returning the message and an attribute for
+// each argument, named "arg0", "arg1", and so on, along with the format as a "template" attribute.
+func ExpandPrintf(format string, args ...any) (string, []slog.Attr) {
+	attrs := make([]slog.Attr, 0, len(args)+1)
+	for i, arg := range args {
+		attrs = append(attrs, slog.Any("arg"+strconv.Itoa(i), arg))
+	}
+	attrs = append(attrs, slog.String(kTemplate, format))
+
+	return fmt.Sprintf(format, args...), attrs
+}
+
+// LogTemplate logs a message rendered from a template with "{name}" placeholders (see
+// ExpandTemplate) at the given level.
+func LogTemplate(ctx context.Context, logger *slog.Logger, level slog.Level, template string, args ...any) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	msg, attrs := ExpandTemplate(template, args...)
+	logExpanded(ctx, logger, level, msg, attrs)
+}
+
+// LogPrintf logs a message rendered with fmt.Sprintf (see ExpandPrintf) at the given level, for
+// call sites migrating from fmt based logging.
+func LogPrintf(ctx context.Context, logger *slog.Logger, level slog.Level, format string, args ...any) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	msg, attrs := ExpandPrintf(format, args...)
+	logExpanded(ctx, logger, level, msg, attrs)
+}
+
+// logExpanded writes a record with the source of the caller of LogTemplate or LogPrintf.
+func logExpanded(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, attrs []slog.Attr) {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func formatTemplateArg(arg any) string {
+	if v, ok := arg.(slog.LogValuer); ok {
+		return slog.AnyValue(v).Resolve().String()
+	}
+	return fmt.Sprint(arg)
+}
+
+// argsToAttrs converts key-value pairs and attributes to attributes, as slog.Record.Add does.
+func argsToAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+
+	var record slog.Record
+	record.Add(args...)
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
@@ -0,0 +1,69 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	t.Run("placeholders", func(t *testing.T) {
+		msg, attrs := sloglambda.ExpandTemplate("order {orderId} shipped to {country}", 42, "NZ", "carrier", "post")
+
+		assert.Equal(t, "order 42 shipped to NZ", msg)
+		assert.Equal(t, []slog.Attr{
+			slog.Int("orderId", 42),
+			slog.String("country", "NZ"),
+			slog.String("template", "order {orderId} shipped to {country}"),
+			slog.String("carrier", "post"),
+		}, attrs)
+	})
+
+	t.Run("literal braces", func(t *testing.T) {
+		msg, attrs := sloglambda.ExpandTemplate(`payload {"id": {id}} {}`, 7)
+
+		assert.Equal(t, `payload {"id": 7} {}`, msg)
+		assert.Equal(t, slog.Int("id", 7), attrs[0])
+	})
+
+	t.Run("missing values", func(t *testing.T) {
+		msg, attrs := sloglambda.ExpandTemplate("user {user} in {team}", "alice")
+
+		assert.Equal(t, "user alice in {team}", msg)
+		assert.Len(t, attrs, 2)
+	})
+}
+
+func TestExpandPrintf(t *testing.T) {
+	msg, attrs := sloglambda.ExpandPrintf("retry %d of %s", 2, "upload")
+
+	assert.Equal(t, "retry 2 of upload", msg)
+	assert.Equal(t, []slog.Attr{
+		slog.Int("arg0", 2),
+		slog.String("arg1", "upload"),
+		slog.String("template", "retry %d of %s"),
+	}, attrs)
+}
+
+func TestLogTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(sloglambda.NewHandler(&buf, sloglambda.WithJSON(), sloglambda.WithoutTime(), sloglambda.WithSource()))
+
+	sloglambda.LogTemplate(context.Background(), logger, slog.LevelInfo, "hello {name}", "world")
+	assert.Contains(t, buf.String(), `"msg":"hello world"`)
+	assert.Contains(t, buf.String(), `"name":"world"`)
+	assert.Contains(t, buf.String(), "template_test.go")
+
+	buf.Reset()
+	sloglambda.LogPrintf(context.Background(), logger, slog.LevelWarn, "%d items", 3)
+	assert.Contains(t, buf.String(), `"msg":"3 items"`)
+	assert.Contains(t, buf.String(), `"arg0":3`)
+
+	buf.Reset()
+	sloglambda.LogTemplate(context.Background(), logger, slog.LevelDebug, "hidden {x}", 1)
+	assert.Empty(t, buf.String())
+}
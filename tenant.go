@@ -0,0 +1,87 @@
+package sloglambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var kTenantID = "tenantId"
+
+type tenantIDKey struct{}
+
+// ContextWithTenantID returns a copy of ctx carrying the given tenant ID.
+//
+// Records logged with the returned context include the tenant ID as a top-level "tenantId" field.
+func ContextWithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantIDFromContext returns the tenant ID stored in ctx by ContextWithTenantID.
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantIDKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// WithTenantExtractor configures the Handler to determine the tenant ID of a record using the given
+// function. An empty result omits the field.
+//
+// The extractor replaces the default behavior of reading the tenant ID stored by
+// ContextWithTenantID.
+func WithTenantExtractor(extract func(ctx context.Context) string) Option {
+	return func(h *Handler) {
+		h.tenantExtractor = extract
+	}
+}
+
+func (h *Handler) tenantID(ctx context.Context) string {
+	if h.tenantExtractor != nil {
+		return h.tenantExtractor(ctx)
+	}
+	tenantID, _ := TenantIDFromContext(ctx)
+	return tenantID
+}
+
+// TenantIDFromEvent extracts a tenant ID from a JSON encoded event using a dot separated path, for
+// example "requestContext.authorizer.tenantId".
+//
+// The value at the path must be a string or a number. Numbers are returned as written in the event,
+// so large numeric IDs are not rounded or written in exponent notation.
+func TenantIDFromEvent(event []byte, path string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(event))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", errors.New("tenant id: invalid event: data after the top level value")
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("tenant id path %q: %q is not an object", path, key)
+		}
+		if value, ok = object[key]; !ok {
+			return "", fmt.Errorf("tenant id path %q: %q not found", path, key)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("tenant id path %q: unsupported value type %T", path, value)
+	}
+}
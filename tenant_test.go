@@ -0,0 +1,68 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithTenantID(t *testing.T) {
+	ctx := sloglambda.ContextWithTenantID(context.Background(), "tenant-1")
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	logger.InfoContext(ctx, t.Name())
+
+	assert.Contains(t, buffer.String(), `"tenantId":"tenant-1"`)
+}
+
+func TestWithTenantExtractor(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithTenantExtractor(func(context.Context) string {
+		return "tenant-2"
+	})))
+
+	logger.Info(t.Name())
+
+	assert.Contains(t, buffer.String(), `"tenantId":"tenant-2"`)
+}
+
+func TestTenantIDFromEvent(t *testing.T) {
+	event := []byte(`{"requestContext":{"authorizer":{"tenantId":"tenant-3","account":42,"org":1234567,"customer":123456789012345678}}}`)
+
+	t.Run("string", func(t *testing.T) {
+		id, err := sloglambda.TenantIDFromEvent(event, "requestContext.authorizer.tenantId")
+
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-3", id)
+	})
+
+	t.Run("number", func(t *testing.T) {
+		id, err := sloglambda.TenantIDFromEvent(event, "requestContext.authorizer.account")
+
+		require.NoError(t, err)
+		assert.Equal(t, "42", id)
+	})
+
+	t.Run("large number", func(t *testing.T) {
+		id, err := sloglambda.TenantIDFromEvent(event, "requestContext.authorizer.org")
+		require.NoError(t, err)
+		assert.Equal(t, "1234567", id)
+
+		id, err = sloglambda.TenantIDFromEvent(event, "requestContext.authorizer.customer")
+		require.NoError(t, err)
+		assert.Equal(t, "123456789012345678", id)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := sloglambda.TenantIDFromEvent(event, "requestContext.tenant")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,159 @@
+package sloglambda
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+var (
+	kTraceID = "trace_id"
+	kSpanID  = "span_id"
+	kBaggage = "baggage"
+)
+
+// TraceContext is the W3C trace context propagated through the traceparent and tracestate headers.
+type TraceContext struct {
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 lowercase hex characters
+	Flags   byte
+	State   string // the raw tracestate header, if any
+}
+
+// ParseTraceparent parses a W3C traceparent header of the form
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseTraceparent(traceparent string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	if len(traceID) != 32 || !isLowerHex(traceID) || traceID == strings.Repeat("0", 32) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent trace id %q", traceID)
+	}
+	if len(spanID) != 16 || !isLowerHex(spanID) || spanID == strings.Repeat("0", 16) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent span id %q", spanID)
+	}
+	f, err := hex.DecodeString(flags)
+	if err != nil || len(f) != 1 {
+		return TraceContext{}, fmt.Errorf("invalid traceparent flags %q", flags)
+	}
+
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Flags:   f[0],
+	}, nil
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+type traceContextKey struct{}
+
+type baggageKey struct{}
+
+// ContextWithTraceContext returns a copy of ctx carrying the trace context parsed from the given
+// traceparent and tracestate headers.
+func ContextWithTraceContext(ctx context.Context, traceparent, tracestate string) (context.Context, error) {
+	tc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+	tc.State = tracestate
+	return context.WithValue(ctx, traceContextKey{}, tc), nil
+}
+
+// TraceContextFromContext returns the trace context stored in ctx by ContextWithTraceContext.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// ContextWithBaggage returns a copy of ctx carrying the members of the given W3C baggage header,
+// for example "userId=alice,isProduction=false". Member properties are ignored.
+func ContextWithBaggage(ctx context.Context, baggage string) (context.Context, error) {
+	members := make(map[string]string)
+
+	var errs []error
+	for _, member := range strings.Split(baggage, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		if strings.TrimSpace(member) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			errs = append(errs, fmt.Errorf("invalid baggage member %q", member))
+			continue
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid baggage member %q: %w", member, err))
+			continue
+		}
+		members[key] = value
+	}
+
+	if len(members) > 0 {
+		ctx = context.WithValue(ctx, baggageKey{}, members)
+	}
+	return ctx, errors.Join(errs...)
+}
+
+// BaggageFromContext returns the baggage members stored in ctx by ContextWithBaggage.
+func BaggageFromContext(ctx context.Context) (map[string]string, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	members, ok := ctx.Value(baggageKey{}).(map[string]string)
+	return members, ok
+}
+
+// WithTraceContext configures the Handler to include the W3C trace context stored in the record's
+// context as top-level "trace_id" and "span_id" fields, and the baggage members as a "baggage"
+// group.
+//
+// HTTPMiddleware stores the trace context and baggage from the incoming request headers.
+func WithTraceContext() Option {
+	return func(h *Handler) {
+		h.traceContext = true
+	}
+}
+
+func traceContextAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+
+	if tc, ok := TraceContextFromContext(ctx); ok {
+		attrs = append(attrs, slog.String(kTraceID, tc.TraceID), slog.String(kSpanID, tc.SpanID))
+	}
+
+	if members, ok := BaggageFromContext(ctx); ok {
+		group := make([]slog.Attr, 0, len(members))
+		for key, value := range members {
+			group = append(group, slog.String(key, value))
+		}
+		attrs = append(attrs, slog.Attr{Key: kBaggage, Value: slog.GroupValue(group...)})
+	}
+
+	return attrs
+}
@@ -0,0 +1,72 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tc, err := sloglambda.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		require.NoError(t, err)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", tc.SpanID)
+		assert.Equal(t, byte(1), tc.Flags)
+	})
+
+	for _, traceparent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	} {
+		t.Run(traceparent, func(t *testing.T) {
+			_, err := sloglambda.ParseTraceparent(traceparent)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWithTraceContext(t *testing.T) {
+	ctx, err := sloglambda.ContextWithTraceContext(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	require.NoError(t, err)
+	ctx, err = sloglambda.ContextWithBaggage(ctx, "userId=alice, region=us%20east;ttl=10")
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithTraceContext()))
+
+	logger.InfoContext(ctx, t.Name())
+
+	assert.Contains(t, buffer.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Contains(t, buffer.String(), `"span_id":"00f067aa0ba902b7"`)
+	assert.Contains(t, buffer.String(), `"baggage":{"region":"us east","userId":"alice"}`)
+}
+
+func TestHTTPMiddleware_traceContext(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithTraceContext()))
+
+	handler := sloglambda.HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	request.Header.Set("baggage", "userId=alice")
+
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Contains(t, buffer.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Contains(t, buffer.String(), `"baggage":{"userId":"alice"}`)
+}
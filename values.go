@@ -0,0 +1,102 @@
+package sloglambda
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// EpochMillis is a time that is encoded as the integer number of milliseconds since the Unix epoch.
+//
+//	logger.Info("order placed", "placedAt", sloglambda.EpochMillis(order.PlacedAt))
+type EpochMillis time.Time
+
+// LogValue implements slog.LogValuer.
+func (t EpochMillis) LogValue() slog.Value {
+	return slog.Int64Value(time.Time(t).UnixMilli())
+}
+
+// RawJSON is an already encoded JSON value that the Handler embeds verbatim in JSON records instead
+// of encoding it as a string. In text records, and when the bytes are not valid JSON, it is written
+// as a string.
+type RawJSON []byte
+
+func (r RawJSON) normalize() any {
+	if json.Valid(r) {
+		return json.RawMessage(r)
+	}
+	return string(r)
+}
+
+// redactedPlaceholder replaces the value of attributes wrapped with Redacted.
+const redactedPlaceholder = "[REDACTED]"
+
+// Redacted wraps a value so it is written as "[REDACTED]" instead of its contents. The wrapped value
+// is never formatted, so sensitive data can be attached at call sites without reaching the logs.
+func Redacted(v any) slog.LogValuer {
+	return redactedValue{}
+}
+
+type redactedValue struct{}
+
+func (redactedValue) LogValue() slog.Value {
+	return slog.StringValue(redactedPlaceholder)
+}
+
+// Lazy returns a value computed by f only when a record it is logged with is written, so expensive
+// attributes cost nothing when the record is dropped by the level, sampling, or budget:
+//
+//	logger.Debug("cache state", "entries", sloglambda.Lazy(cache.Snapshot))
+//
+// f is called at most once and its result is reused for every record the value is logged with. A
+// Lazy value given to Logger.With is computed for the first record written and repeated in later
+// ones, so create the value at the call site when it should be computed for each record.
+func Lazy(f func() slog.Value) slog.LogValuer {
+	return &lazyValue{f: f}
+}
+
+type lazyValue struct {
+	once  sync.Once
+	f     func() slog.Value
+	value slog.Value
+}
+
+func (l *lazyValue) LogValue() slog.Value {
+	l.once.Do(func() {
+		l.value = l.f()
+	})
+	return l.value
+}
+
+// Verbose returns an attribute wrapping attrs that a Handler only writes when its level is DEBUG or
+// lower, so call sites can always attach detailed attributes and leave the cost of encoding them to
+// the Handler's configuration:
+//
+//	logger.Info("order placed", "orderId", order.ID, sloglambda.Verbose(slog.Any("order", order)))
+//
+// The wrapped attributes are written as if they were given directly, in the logger's current
+// group. Only attributes given to the logger or record are checked; Verbose attributes nested in a
+// group, and those written by other handlers, are always included.
+func Verbose(attrs ...slog.Attr) slog.Attr {
+	return slog.Any("", &verboseAttrs{attrs: attrs})
+}
+
+type verboseAttrs struct {
+	attrs []slog.Attr
+}
+
+func (v *verboseAttrs) LogValue() slog.Value {
+	return slog.GroupValue(v.attrs...)
+}
+
+// logTypeOverride is the value of the attribute created by Type.
+type logTypeOverride string
+
+// Type returns an attribute that overrides the Handler's "type" field (see WithType) for the record
+// it is logged with, or for every record of a logger created with it:
+//
+//	logger.Info("user signed in", sloglambda.Type("audit.log"))
+func Type(logType string) slog.Attr {
+	return slog.Any(kLambdaLogType, logTypeOverride(logType))
+}
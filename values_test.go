@@ -0,0 +1,141 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochMillis(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	logger.Info(t.Name(), "at", sloglambda.EpochMillis(time.UnixMilli(1717243200123)))
+
+	assert.Contains(t, buffer.String(), `"at":1717243200123`)
+}
+
+func TestRawJSON(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+		logger.Info(t.Name(), "payload", sloglambda.RawJSON(`{"id":1}`), "invalid", sloglambda.RawJSON(`{`))
+
+		assert.Contains(t, buffer.String(), `"payload":{"id":1}`)
+		assert.Contains(t, buffer.String(), `"invalid":"{"`)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithText()))
+
+		logger.Info(t.Name(), "payload", sloglambda.RawJSON(`{"id":1}`))
+
+		assert.Contains(t, buffer.String(), `payload="{\"id\":1}"`)
+	})
+}
+
+func TestRedacted(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON()))
+
+	logger.Info(t.Name(), "password", sloglambda.Redacted("hunter2"))
+
+	assert.Contains(t, buffer.String(), `"password":"[REDACTED]"`)
+	assert.NotContains(t, buffer.String(), "hunter2")
+}
+
+func TestLazy(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithMessageSampling(1, 0)))
+
+	calls := 0
+	lazy := func() slog.LogValuer {
+		return sloglambda.Lazy(func() slog.Value {
+			calls++
+			return slog.IntValue(42)
+		})
+	}
+
+	logger.Debug(t.Name(), "state", lazy())
+	assert.Zero(t, calls)
+
+	logger.Info(t.Name(), "state", lazy())
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, buffer.String(), `"state":42`)
+
+	logger.Info(t.Name(), "state", lazy())
+	assert.Equal(t, 1, calls, "sampled records do not evaluate the value")
+
+	t.Run("computed once for every record", func(t *testing.T) {
+		buffer.Reset()
+		calls := 0
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON())).With("state", sloglambda.Lazy(func() slog.Value {
+			calls++
+			return slog.IntValue(calls)
+		}))
+
+		logger.Info("first")
+		logger.Info("second")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, strings.Count(buffer.String(), `"state":1`))
+	})
+}
+
+func TestType(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON())).WithGroup("group")
+
+	logger.Info("audit", sloglambda.Type("audit.log"), "user", "alice")
+	assert.Contains(t, buffer.String(), `"type":"audit.log"`)
+	assert.Contains(t, buffer.String(), `"group":{"user":"alice"}`)
+	buffer.Reset()
+
+	logger.With(sloglambda.Type("access.log")).Info("access")
+	assert.Contains(t, buffer.String(), `"type":"access.log"`)
+	buffer.Reset()
+
+	logger.Info("app")
+	assert.Contains(t, buffer.String(), `"type":"app.log"`)
+}
+
+func TestVerbose(t *testing.T) {
+	t.Run("omitted above DEBUG", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithoutTime()))
+
+		logger.Info("order placed", "orderId", 42, sloglambda.Verbose(slog.String("items", "a,b")))
+
+		assert.Equal(t, `{"level":"INFO","msg":"order placed","orderId":42,"record":{"functionName":"test-function","version":"$LATEST"},"type":"app.log"}`+"\n", buffer.String())
+	})
+
+	t.Run("included at DEBUG", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(slog.LevelDebug))).WithGroup("order")
+
+		logger.Info("order placed", "orderId", 42, sloglambda.Verbose(slog.String("items", "a,b"), slog.Int("count", 2)))
+
+		assert.Contains(t, buffer.String(), `"order":{"count":2,"items":"a,b","orderId":42}`)
+	})
+
+	t.Run("follows the level", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		level := new(slog.LevelVar)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithLevel(level))).
+			With(sloglambda.Verbose(slog.String("detail", "rich")))
+
+		logger.Info("first")
+		assert.NotContains(t, buffer.String(), "detail")
+
+		level.Set(slog.LevelDebug)
+		logger.Info("second")
+		assert.Contains(t, buffer.String(), `"detail":"rich"`)
+	})
+}
@@ -0,0 +1,98 @@
+package sloglambda
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// Annotator is implemented by trace segments that accept annotations and metadata, such as
+// *xray.Segment from aws-xray-sdk-go.
+type Annotator interface {
+	AddAnnotation(key string, value any) error
+	AddMetadata(key string, value any) error
+}
+
+type annotations struct {
+	segment func(context.Context) Annotator
+	keys    []string
+}
+
+// WithAnnotations configures the Handler to mirror the attributes with the given keys onto the
+// active trace segment as they are logged, for example:
+//
+//	sloglambda.WithAnnotations(func(ctx context.Context) sloglambda.Annotator {
+//		if seg := xray.GetSegment(ctx); seg != nil {
+//			return seg
+//		}
+//		return nil
+//	}, "orderId", "customerId")
+//
+// String, boolean, and numeric values are added as annotations so they are searchable, any other
+// value is added as metadata. Attributes are matched by key regardless of the group they are in.
+func WithAnnotations(segment func(ctx context.Context) Annotator, keys ...string) Option {
+	return func(h *Handler) {
+		if segment == nil {
+			h.invalidOption("WithAnnotations: nil segment function")
+			return
+		}
+		h.annotations = &annotations{
+			segment: segment,
+			keys:    keys,
+		}
+	}
+}
+
+// mirror adds the record's designated attributes to the active segment.
+func (a *annotations) mirror(ctx context.Context, f *valueFormat, gattr []groupOrAttrs, record slog.Record) {
+	segment := a.segment(ctx)
+	if isNil(segment) {
+		return
+	}
+
+	var visit func(attr slog.Attr)
+	visit = func(attr slog.Attr) {
+		attr.Value = attr.Value.Resolve()
+		if attr.Value.Kind() == slog.KindGroup {
+			for _, a := range attr.Value.Group() {
+				visit(a)
+			}
+			return
+		}
+		if !slices.Contains(a.keys, attr.Key) {
+			return
+		}
+
+		switch value := f.normalize(attr.Value); value.(type) {
+		case string, bool, int64, uint64, float64:
+			_ = segment.AddAnnotation(attr.Key, value)
+		default:
+			_ = segment.AddMetadata(attr.Key, value)
+		}
+	}
+
+	for _, ga := range gattr {
+		for _, attr := range ga.attrs {
+			visit(attr)
+		}
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		visit(attr)
+		return true
+	})
+}
+
+// isNil reports whether v is nil, including interfaces holding a nil pointer, map, slice, channel,
+// or function.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package sloglambda_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	sloglambda "github.com/maddiesch/slog-lambda"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSegment struct {
+	annotations map[string]any
+	metadata    map[string]any
+}
+
+func (s *testSegment) AddAnnotation(key string, value any) error {
+	s.annotations[key] = value
+	return nil
+}
+
+func (s *testSegment) AddMetadata(key string, value any) error {
+	s.metadata[key] = value
+	return nil
+}
+
+func TestWithAnnotations(t *testing.T) {
+	t.Run("with a segment", func(t *testing.T) {
+		segment := &testSegment{annotations: map[string]any{}, metadata: map[string]any{}}
+
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithAnnotations(func(context.Context) sloglambda.Annotator {
+			return segment
+		}, "orderId", "items", "customer")))
+
+		logger.With("customer", "c-1").WithGroup("order").Info(t.Name(), "orderId", "o-1", "items", []string{"a"}, "other", true)
+
+		assert.Equal(t, map[string]any{"orderId": "o-1", "customer": "c-1"}, segment.annotations)
+		assert.Equal(t, map[string]any{"items": []string{"a"}}, segment.metadata)
+		assert.Contains(t, buffer.String(), `"orderId":"o-1"`)
+	})
+
+	t.Run("without a segment", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		logger := slog.New(sloglambda.NewHandler(buffer, sloglambda.WithJSON(), sloglambda.WithAnnotations(func(context.Context) sloglambda.Annotator {
+			var segment *testSegment
+			return segment
+		}, "orderId")))
+
+		logger.Info(t.Name(), "orderId", "o-1")
+
+		assert.Contains(t, buffer.String(), `"orderId":"o-1"`)
+	})
+}